@@ -1,31 +1,130 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"strconv"
 
+	"github.com/srgyrn/lucky-38/pkg/commitment"
 	"github.com/srgyrn/lucky-38/pkg/config"
 	"github.com/srgyrn/lucky-38/pkg/creating"
 	"github.com/srgyrn/lucky-38/pkg/drawing"
+	"github.com/srgyrn/lucky-38/pkg/history"
 	"github.com/srgyrn/lucky-38/pkg/listing"
 	"github.com/srgyrn/lucky-38/pkg/rest"
 	"github.com/srgyrn/lucky-38/pkg/storage"
+	"github.com/srgyrn/lucky-38/pkg/storage/memory"
 )
 
+// repository is implemented by every storage backend the croupier can run on
+type repository interface {
+	creating.Repository
+	listing.Repository
+	drawing.Repository
+	history.Repository
+	commitment.Repository
+}
+
+const usage = `usage: deck_api [migrate [up | down [steps] | version]]`
+
 func main() {
 	conf, err := config.Load(".")
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 
-	repository, err := storage.NewRepository(conf.Driver, conf.Source)
+	if 1 < len(os.Args) {
+		if "migrate" != os.Args[1] {
+			log.Fatal(usage)
+		}
+
+		if err := migrate(conf, os.Args[2:]); err != nil {
+			log.Fatal(err.Error())
+		}
+		return
+	}
+
+	repository, err := newRepository(conf)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 
-	router := rest.Handler(creating.NewService(repository), listing.NewService(repository), drawing.NewService(repository))
+	router := rest.Handler(
+		creating.NewService(repository),
+		listing.NewService(repository),
+		drawing.NewService(repository),
+		history.NewService(repository),
+		commitment.NewService(repository),
+		conf.AdminToken,
+	)
 
 	fmt.Printf("Your digital croupier is now available at: localhost:3000\n")
 	log.Fatal(http.ListenAndServe(":3000", router))
 }
+
+// newRepository returns the storage backend selected by DB_DRIVER. SQL backends are migrated to the latest schema.
+func newRepository(conf config.Config) (repository, error) {
+	if config.DriverMemory == conf.Driver {
+		return memory.NewRepository(), nil
+	}
+
+	r, err := storage.NewRepository(conf.Driver, conf.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := r.MigrateUp()
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("Database schema is at version %d\n", version)
+
+	return r, nil
+}
+
+// migrate runs the migrate subcommand: "up" (default) applies pending migrations, "down" reverts the given number
+// of migrations (1 by default) and "version" prints the current schema version.
+func migrate(conf config.Config, args []string) error {
+	if config.DriverMemory == conf.Driver {
+		fmt.Println("Nothing to migrate, memory driver has no schema")
+		return nil
+	}
+
+	r, err := storage.NewRepository(conf.Driver, conf.Source)
+	if err != nil {
+		return err
+	}
+
+	command := "up"
+	if 0 < len(args) {
+		command = args[0]
+	}
+
+	var version int
+	switch command {
+	case "up":
+		version, err = r.MigrateUp()
+	case "down":
+		steps := 1
+		if 1 < len(args) {
+			if steps, err = strconv.Atoi(args[1]); err != nil || 1 > steps {
+				return fmt.Errorf("invalid number of steps: %s", args[1])
+			}
+		}
+		version, err = r.MigrateDown(steps)
+	case "version":
+		version, err = r.SchemaVersion()
+	default:
+		return errors.New(usage)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Database schema is at version %d\n", version)
+	return nil
+}
@@ -1,25 +1,4 @@
-CREATE TABLE IF NOT EXISTS public.decks
-(
-    deck_id   UUID PRIMARY KEY,
-    shuffled  BOOLEAN NOT NULL DEFAULT false,
-    remaining INTEGER NOT NULL DEFAULT 52
-);
-
-CREATE TABLE IF NOT EXISTS public.cards
-(
-    card_id SERIAL PRIMARY KEY,
-    code    VARCHAR(3)  NOT NULL,
-    value   VARCHAR(10) NOT NULL,
-    suit    VARCHAR(10) NOT NULL,
-    drawn   BOOLEAN     NOT NULL DEFAULT false,
-    deck    UUID        NOT NULL,
-
-    CONSTRAINT fk_card_deck
-        FOREIGN KEY (deck)
-            REFERENCES decks (deck_id)
-            ON DELETE CASCADE
-            DEFERRABLE INITIALLY DEFERRED
-);
-
+-- Tables are created and evolved by the migrations in pkg/storage, which the api applies on boot.
+-- See "deck_api migrate" to run them by hand.
 DROP DATABASE IF EXISTS lucky_test;
-CREATE DATABASE lucky_test WITH TEMPLATE lucky OWNER db_admin;
\ No newline at end of file
+CREATE DATABASE lucky_test OWNER db_admin;
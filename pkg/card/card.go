@@ -0,0 +1,221 @@
+// Package card parses and formats codes of French playing cards.
+//
+// A code is a rank followed by a suit, e.g. AS, 10H or QC. Parsing is lenient: ranks and suits are case insensitive,
+// T stands for ten and suits may be given by their symbols, e.g. t♥. Formatting always gives the canonical code, i.e.
+// upper case, 10 for ten and letters for suits.
+package card
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type (
+	// Rank is the numeric rank of a card, from Ace as 1 up to King as 13
+	Rank int
+
+	// Suit is one of the four French suits, in the order full decks are generated
+	Suit int
+
+	// Color is the color of a suit
+	Color int
+
+	// Card is a French playing card
+	Card struct {
+		Rank Rank
+		Suit Suit
+	}
+)
+
+const (
+	Ace Rank = iota + 1
+	Two
+	Three
+	Four
+	Five
+	Six
+	Seven
+	Eight
+	Nine
+	Ten
+	Jack
+	Queen
+	King
+)
+
+const (
+	Spades Suit = iota + 1
+	Diamonds
+	Clubs
+	Hearts
+)
+
+const (
+	Black Color = iota + 1
+	Red
+)
+
+// Ranks holds every rank in order
+var Ranks = []Rank{Ace, Two, Three, Four, Five, Six, Seven, Eight, Nine, Ten, Jack, Queen, King}
+
+// Suits holds every suit in order
+var Suits = []Suit{Spades, Diamonds, Clubs, Hearts}
+
+var ErrInvalidCode = errors.New("invalid card code")
+
+var faces = map[Rank]struct{ code, name string }{
+	Ace:   {"A", "ACE"},
+	Jack:  {"J", "JACK"},
+	Queen: {"Q", "QUEEN"},
+	King:  {"K", "KING"},
+}
+
+var suits = map[Suit]struct {
+	code    string
+	name    string
+	symbols string
+	color   Color
+}{
+	Spades:   {"S", "SPADES", "♠♤", Black},
+	Diamonds: {"D", "DIAMONDS", "♦♢", Red},
+	Clubs:    {"C", "CLUBS", "♣♧", Black},
+	Hearts:   {"H", "HEARTS", "♥♡", Red},
+}
+
+// Parse returns the card with given code. If the code is not a French playing card, ErrInvalidCode is returned.
+func Parse(code string) (Card, error) {
+	trimmed := strings.ToUpper(strings.TrimSpace(code))
+	symbol, size := utf8.DecodeLastRuneInString(trimmed)
+	if utf8.RuneError == symbol || size == len(trimmed) {
+		return Card{}, fmt.Errorf("%w: %q", ErrInvalidCode, code)
+	}
+
+	suit, ok := parseSuit(symbol)
+	if !ok {
+		return Card{}, fmt.Errorf("%w: %q has no suit", ErrInvalidCode, code)
+	}
+
+	rank, ok := parseRank(trimmed[:len(trimmed)-size])
+	if !ok {
+		return Card{}, fmt.Errorf("%w: %q has no rank", ErrInvalidCode, code)
+	}
+
+	return Card{Rank: rank, Suit: suit}, nil
+}
+
+// Canonical returns the canonical code of given code if it is a French playing card, the code itself otherwise
+func Canonical(code string) string {
+	c, err := Parse(code)
+	if err != nil {
+		return code
+	}
+
+	return c.Code()
+}
+
+// Deck returns the 52 cards of a French deck, suit by suit, each suit from Ace to King
+func Deck() []Card {
+	cards := make([]Card, 0, len(Suits)*len(Ranks))
+	for _, s := range Suits {
+		for _, r := range Ranks {
+			cards = append(cards, Card{Rank: r, Suit: s})
+		}
+	}
+
+	return cards
+}
+
+// Code returns the canonical code of the card, e.g. 10H
+func (c Card) Code() string {
+	return c.Rank.Code() + c.Suit.Code()
+}
+
+// Value returns the display name of the rank of the card, e.g. ACE or 10
+func (c Card) Value() string {
+	return c.Rank.String()
+}
+
+// String returns the card in words, e.g. QUEEN OF HEARTS
+func (c Card) String() string {
+	return c.Rank.String() + " OF " + c.Suit.String()
+}
+
+// Code returns the code of the rank, e.g. A or 10
+func (r Rank) Code() string {
+	if face, ok := faces[r]; ok {
+		return face.code
+	}
+
+	return strconv.Itoa(int(r))
+}
+
+// String returns the display name of the rank, e.g. ACE or 10
+func (r Rank) String() string {
+	if face, ok := faces[r]; ok {
+		return face.name
+	}
+
+	return strconv.Itoa(int(r))
+}
+
+// Code returns the letter of the suit, e.g. S
+func (s Suit) Code() string {
+	return suits[s].code
+}
+
+// String returns the name of the suit, e.g. SPADES
+func (s Suit) String() string {
+	return suits[s].name
+}
+
+// Symbol returns the symbol of the suit, e.g. ♠
+func (s Suit) Symbol() string {
+	symbol, _ := utf8.DecodeRuneInString(suits[s].symbols)
+	return string(symbol)
+}
+
+// Color returns the color of the suit
+func (s Suit) Color() Color {
+	return suits[s].color
+}
+
+// String returns the name of the color, e.g. RED
+func (c Color) String() string {
+	switch c {
+	case Black:
+		return "BLACK"
+	case Red:
+		return "RED"
+	}
+
+	return ""
+}
+
+// parseSuit returns the suit with given upper case letter or symbol
+func parseSuit(symbol rune) (Suit, bool) {
+	for _, s := range Suits {
+		if string(symbol) == suits[s].code || strings.ContainsRune(suits[s].symbols, symbol) {
+			return s, true
+		}
+	}
+
+	return 0, false
+}
+
+// parseRank returns the rank with given upper case code, T stands for ten
+func parseRank(code string) (Rank, bool) {
+	if "T" == code {
+		return Ten, true
+	}
+
+	for _, r := range Ranks {
+		if code == r.Code() {
+			return r, true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,103 @@
+package card
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		code    string
+		want    Card
+		wantErr error
+	}{
+		{code: "AS", want: Card{Rank: Ace, Suit: Spades}},
+		{code: "10H", want: Card{Rank: Ten, Suit: Hearts}},
+		{code: "th", want: Card{Rank: Ten, Suit: Hearts}},
+		{code: " qc ", want: Card{Rank: Queen, Suit: Clubs}},
+		{code: "7♦", want: Card{Rank: Seven, Suit: Diamonds}},
+		{code: "k♤", want: Card{Rank: King, Suit: Spades}},
+		{code: "J♥", want: Card{Rank: Jack, Suit: Hearts}},
+		{code: "1S", wantErr: ErrInvalidCode},
+		{code: "11S", wantErr: ErrInvalidCode},
+		{code: "14H", wantErr: ErrInvalidCode},
+		{code: "10K", wantErr: ErrInvalidCode},
+		{code: "S", wantErr: ErrInvalidCode},
+		{code: "♠", wantErr: ErrInvalidCode},
+		{code: "", wantErr: ErrInvalidCode},
+		{code: "X1", wantErr: ErrInvalidCode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			got, err := Parse(tt.code)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parse() error = %v, want %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	tests := map[string]string{
+		"as":  "AS",
+		"T♣":  "10C",
+		"10d": "10D",
+		"X1":  "X1",
+		"1O":  "1O",
+	}
+	for code, want := range tests {
+		if got := Canonical(code); got != want {
+			t.Errorf("Canonical(%q) = %s, want %s", code, got, want)
+		}
+	}
+}
+
+func TestCard(t *testing.T) {
+	tests := []struct {
+		card   Card
+		code   string
+		value  string
+		string string
+		symbol string
+		color  Color
+	}{
+		{card: Card{Rank: Ace, Suit: Spades}, code: "AS", value: "ACE", string: "ACE OF SPADES", symbol: "♠", color: Black},
+		{card: Card{Rank: Ten, Suit: Diamonds}, code: "10D", value: "10", string: "10 OF DIAMONDS", symbol: "♦", color: Red},
+		{card: Card{Rank: Queen, Suit: Clubs}, code: "QC", value: "QUEEN", string: "QUEEN OF CLUBS", symbol: "♣", color: Black},
+		{card: Card{Rank: King, Suit: Hearts}, code: "KH", value: "KING", string: "KING OF HEARTS", symbol: "♥", color: Red},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			c := tt.card
+			if c.Code() != tt.code || c.Value() != tt.value || c.String() != tt.string || c.Suit.Symbol() != tt.symbol || c.Suit.Color() != tt.color {
+				t.Errorf("card = %s %s %s %s %v, want %s %s %s %s %v",
+					c.Code(), c.Value(), c, c.Suit.Symbol(), c.Suit.Color(), tt.code, tt.value, tt.string, tt.symbol, tt.color)
+			}
+
+			if parsed, err := Parse(c.Code()); err != nil || parsed != c {
+				t.Errorf("Parse(%s) = %v, %v, want %v", c.Code(), parsed, err, c)
+			}
+		})
+	}
+}
+
+func TestDeck(t *testing.T) {
+	deck := Deck()
+	if 52 != len(deck) {
+		t.Fatalf("Deck() has %d cards, want 52", len(deck))
+	}
+
+	seen := make(map[Card]bool)
+	for _, c := range deck {
+		seen[c] = true
+	}
+
+	if 52 != len(seen) || "AS" != deck[0].Code() || "KH" != deck[51].Code() {
+		t.Errorf("Deck() = %v, want 52 distinct cards from AS to KH", deck)
+	}
+}
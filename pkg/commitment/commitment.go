@@ -0,0 +1,53 @@
+// Package commitment lets players verify that a deck was not reordered after it was created.
+//
+// When a deck is created, the croupier publishes a commitment: the SHA-256 hash of a secret salt and the full order
+// of the cards with their orientation. Once the deck is exhausted or closed, the salt and the order are revealed, and anyone can recompute
+// the hash with Verify.
+package commitment
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// SaltSize is the number of random bytes in a salt
+const SaltSize = 32
+
+// ReversedSuffix follows the code of an upside down card in the committed order. Card codes have no spaces, so it can
+// not be mistaken for a part of a code.
+const ReversedSuffix = " R"
+
+// NewSalt returns SaltSize random bytes, hex encoded
+func NewSalt() (string, error) {
+	salt := make([]byte, SaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("error at reading salt: %v", err)
+	}
+
+	return hex.EncodeToString(salt), nil
+}
+
+// Entry returns code as it is committed to, followed by ReversedSuffix if the card is upside down, e.g. "0M R"
+func Entry(code string, reversed bool) string {
+	if reversed {
+		return code + ReversedSuffix
+	}
+
+	return code
+}
+
+// Compute returns the hex encoded SHA-256 hash of salt, a new line and card codes joined with commas in deck order,
+// i.e. sha256("<salt>\nAS,2S,3S"). Codes of upside down cards are given as Entry returns them.
+func Compute(salt string, codes []string) string {
+	sum := sha256.Sum256([]byte(salt + "\n" + strings.Join(codes, ",")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Verify reports if commitment is the one computed from salt and codes in deck order
+func Verify(commitment, salt string, codes []string) bool {
+	return 1 == subtle.ConstantTimeCompare([]byte(strings.ToLower(commitment)), []byte(Compute(salt, codes)))
+}
@@ -0,0 +1,66 @@
+package commitment
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestCompute(t *testing.T) {
+	// printf 'salt\nAS,2S,3S' | sha256sum
+	want := "1eddd8923e44b67a8518549f4f7e59c1101aa016ac6586abcc4aada53868a1e6"
+	if got := Compute("salt", []string{"AS", "2S", "3S"}); got != want {
+		t.Errorf("Compute() = %s, want %s", got, want)
+	}
+}
+
+func TestEntry(t *testing.T) {
+	if got := Entry("0M", false); "0M" != got {
+		t.Errorf("Entry() = %q, want 0M", got)
+	}
+
+	if got := Entry("0M", true); "0M R" != got {
+		t.Errorf("Entry() = %q, want \"0M R\"", got)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	codes := []string{"AS", "2S", "3S"}
+	hash := Compute("salt", codes)
+
+	tests := []struct {
+		name       string
+		commitment string
+		salt       string
+		codes      []string
+		want       bool
+	}{
+		{name: "valid", commitment: hash, salt: "salt", codes: codes, want: true},
+		{name: "upper case", commitment: "1EDDD8923E44B67A8518549F4F7E59C1101AA016AC6586ABCC4AADA53868A1E6", salt: "salt", codes: codes, want: true},
+		{name: "reordered", commitment: hash, salt: "salt", codes: []string{"2S", "AS", "3S"}, want: false},
+		{name: "missing card", commitment: hash, salt: "salt", codes: codes[:2], want: false},
+		{name: "wrong salt", commitment: hash, salt: "pepper", codes: codes, want: false},
+		{name: "flipped orientation", commitment: hash, salt: "salt", codes: []string{"AS", Entry("2S", true), "3S"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Verify(tt.commitment, tt.salt, tt.codes); got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSalt(t *testing.T) {
+	first, err := NewSalt()
+	if err != nil {
+		t.Fatalf("NewSalt() error = %v", err)
+	}
+
+	if decoded, err := hex.DecodeString(first); err != nil || SaltSize != len(decoded) {
+		t.Errorf("NewSalt() = %s, want %d hex encoded bytes", first, SaltSize)
+	}
+
+	if second, _ := NewSalt(); first == second {
+		t.Errorf("NewSalt() returned %s twice", first)
+	}
+}
@@ -0,0 +1,97 @@
+package commitment
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// Reveal discloses the salt and the order of the cards a deck was created with, so its commitment can be verified.
+	Reveal struct {
+		DeckID     uuid.UUID `json:"deck_id"`
+		Commitment string    `json:"commitment"`
+		Salt       string    `json:"salt"`
+		Closed     bool      `json:"closed"`
+		Remaining  int       `json:"remaining"`
+		Cards      []Card    `json:"cards"`
+	}
+
+	// Card is a card as the deck was created with. Reversed tells if the card was upside down.
+	Card struct {
+		ID       int    `json:"-"`
+		Code     string `json:"code"`
+		Value    string `json:"value"`
+		Suit     string `json:"suit"`
+		Reversed bool   `json:"reversed,omitempty"`
+	}
+
+	Service interface {
+		Reveal(deckID string) (Reveal, error)
+		Close(deckID string) error
+	}
+
+	Repository interface {
+		// FindReveal returns the commitment of the deck with its cards in the order the deck was created with
+		FindReveal(deckID uuid.UUID) (Reveal, error)
+		CloseDeck(deckID uuid.UUID) error
+	}
+
+	service struct {
+		r Repository
+	}
+)
+
+var ErrNotFound = errors.New("deck not found")
+var ErrNoCommitment = errors.New("deck has no commitment")
+var ErrNotRevealable = errors.New("deck is neither exhausted nor closed")
+
+// Entries returns the cards of the reveal in order as they are committed to, see Entry
+func (r Reveal) Entries() []string {
+	entries := make([]string, 0, len(r.Cards))
+	for _, c := range r.Cards {
+		entries = append(entries, Entry(c.Code, c.Reversed))
+	}
+
+	return entries
+}
+
+func NewService(r Repository) Service {
+	return &service{r: r}
+}
+
+// Reveal returns the salt and the initial card order of the deck with given ID.
+// If deck is not found, ErrNotFound is returned. If the deck was created without a commitment, ErrNoCommitment is
+// returned. Unless the deck is exhausted or closed, ErrNotRevealable is returned.
+func (s *service) Reveal(deckID string) (Reveal, error) {
+	deckUUID, err := uuid.Parse(deckID)
+	if err != nil {
+		return Reveal{}, ErrNotFound
+	}
+
+	reveal, err := s.r.FindReveal(deckUUID)
+	if err != nil {
+		return Reveal{}, err
+	}
+
+	if "" == reveal.Commitment {
+		return Reveal{}, ErrNoCommitment
+	}
+
+	if !reveal.Closed && 0 < reveal.Remaining {
+		return Reveal{}, ErrNotRevealable
+	}
+
+	return reveal, nil
+}
+
+// Close closes the deck with given ID, so no more cards can be drawn from or returned to it and it can be revealed.
+// Closing a closed deck has no effect. If deck is not found, ErrNotFound is returned.
+func (s *service) Close(deckID string) error {
+	deckUUID, err := uuid.Parse(deckID)
+	if err != nil {
+		return ErrNotFound
+	}
+
+	return s.r.CloseDeck(deckUUID)
+}
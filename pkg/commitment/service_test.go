@@ -0,0 +1,132 @@
+package commitment
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func Test_service_Reveal(t *testing.T) {
+	deckID, _ := uuid.Parse("a251071b-662f-44b6-ba11-e24863039c59")
+	cards := []Card{
+		{ID: 1, Code: "AS", Value: "ACE", Suit: "SPADES"},
+		{ID: 2, Code: "2S", Value: "2", Suit: "SPADES"},
+	}
+	reveal := func(closed bool, remaining int) Reveal {
+		return Reveal{
+			DeckID:     deckID,
+			Commitment: Compute("salt", []string{"AS", "2S"}),
+			Salt:       "salt",
+			Closed:     closed,
+			Remaining:  remaining,
+			Cards:      cards,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		r       Repository
+		deckID  string
+		want    Reveal
+		wantErr error
+	}{
+		{
+			name:    "invalid id",
+			r:       &mockRepository{},
+			deckID:  "asdf",
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "deck not found",
+			r:       &mockRepository{err: ErrNotFound},
+			deckID:  deckID.String(),
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "no commitment",
+			r:       &mockRepository{reveal: Reveal{DeckID: deckID, Remaining: 0, Cards: cards}},
+			deckID:  deckID.String(),
+			wantErr: ErrNoCommitment,
+		},
+		{
+			name:    "cards remaining",
+			r:       &mockRepository{reveal: reveal(false, 1)},
+			deckID:  deckID.String(),
+			wantErr: ErrNotRevealable,
+		},
+		{
+			name:   "exhausted",
+			r:      &mockRepository{reveal: reveal(false, 0)},
+			deckID: deckID.String(),
+			want:   reveal(false, 0),
+		},
+		{
+			name:   "closed",
+			r:      &mockRepository{reveal: reveal(true, 2)},
+			deckID: deckID.String(),
+			want:   reveal(true, 2),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewService(tt.r).Reveal(tt.deckID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Reveal() error = %v, want %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Reveal() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_service_Close(t *testing.T) {
+	deckID, _ := uuid.Parse("a251071b-662f-44b6-ba11-e24863039c59")
+
+	tests := []struct {
+		name    string
+		r       *mockRepository
+		deckID  string
+		wantErr error
+	}{
+		{name: "invalid id", r: &mockRepository{}, deckID: "asdf", wantErr: ErrNotFound},
+		{name: "deck not found", r: &mockRepository{err: ErrNotFound}, deckID: deckID.String(), wantErr: ErrNotFound},
+		{name: "valid", r: &mockRepository{}, deckID: deckID.String()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewService(tt.r).Close(tt.deckID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Close() error = %v, want %v", err, tt.wantErr)
+				return
+			}
+
+			if nil == tt.wantErr && deckID != tt.r.closed {
+				t.Errorf("Close() closed %v, want %v", tt.r.closed, deckID)
+			}
+		})
+	}
+}
+
+type mockRepository struct {
+	err    error
+	reveal Reveal
+	closed uuid.UUID
+}
+
+func (r *mockRepository) FindReveal(uuid.UUID) (Reveal, error) {
+	return r.reveal, r.err
+}
+
+func (r *mockRepository) CloseDeck(deckID uuid.UUID) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	r.closed = deckID
+	return nil
+}
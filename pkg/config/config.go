@@ -8,10 +8,18 @@ import (
 	"github.com/joho/godotenv"
 )
 
+// Supported values for DB_DRIVER
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite3"
+	DriverMemory   = "memory"
+)
+
 // Config holds required environment variables
 type Config struct {
-	Driver string
-	Source string
+	Driver     string
+	Source     string
+	AdminToken string
 }
 
 // Load sets content of configuration file to ENV, reads them and returns Config
@@ -37,8 +45,9 @@ func Load(path string) (Config, error) {
 	}
 
 	return Config{
-		Driver: os.Getenv("DB_DRIVER"),
-		Source: os.Getenv("DB_SOURCE"),
+		Driver:     os.Getenv("DB_DRIVER"),
+		Source:     os.Getenv("DB_SOURCE"),
+		AdminToken: os.Getenv("ADMIN_TOKEN"),
 	}, nil
 }
 
@@ -38,8 +38,9 @@ func TestLoad(t *testing.T) {
 			name:   "load test",
 			appEnv: "test",
 			want: config.Config{
-				Driver: "postgres",
-				Source: "postgresql://db_admin:admin321@db/lucky_test?sslmode=disable",
+				Driver:     "postgres",
+				Source:     "postgresql://db_admin:admin321@db/lucky_test?sslmode=disable",
+				AdminToken: "secret",
 			},
 			wantErr: false,
 		},
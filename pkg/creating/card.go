@@ -1,8 +1,13 @@
 package creating
 
+// Card is a card of a deck. Copy tells copies of a card apart, starting from 1: the copies a type has of a card,
+// e.g. two in pinochle, then the decks of a shoe. It is 0 for cards a single deck has once.
+// Reversed tells if the card is upside down in a deck created with reversals.
 type Card struct {
-	ID     int
-	Code   string
-	Value  string
-	Suit   string
-}
\ No newline at end of file
+	ID       int    `json:"-"`
+	Code     string `json:"code"`
+	Value    string `json:"value"`
+	Suit     string `json:"suit"`
+	Copy     int    `json:"copy,omitempty"`
+	Reversed bool   `json:"reversed,omitempty"`
+}
@@ -3,8 +3,19 @@ package creating
 import "github.com/google/uuid"
 
 type Deck struct {
-	ID        uuid.UUID `json:"deck_id"`
-	Shuffled  bool      `json:"shuffled"`
-	Remaining int       `json:"remaining"`
-	Cards     []Card    `json:"-"`
+	ID              uuid.UUID `json:"deck_id"`
+	Type            string    `json:"type,omitempty"`
+	Shuffled        bool      `json:"shuffled"`
+	Remaining       int       `json:"remaining"`
+	Seed            *int64    `json:"seed,omitempty"`
+	Jokers          int       `json:"jokers,omitempty"`
+	Decks           int       `json:"decks,omitempty"`
+	Reversals       bool      `json:"reversals,omitempty"`
+	AllowDuplicates bool      `json:"allow_duplicates,omitempty"`
+	Penetration     int       `json:"penetration,omitempty"`
+	CutCard         int       `json:"cut_card,omitempty"`
+	Filter          Filter    `json:"-"`
+	Commitment      string    `json:"commitment"`
+	Salt            string    `json:"-"`
+	Cards           []Card    `json:"-"`
 }
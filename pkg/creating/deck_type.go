@@ -0,0 +1,212 @@
+package creating
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/srgyrn/lucky-38/pkg/card"
+)
+
+// Names of the built-in deck types
+const (
+	FrenchDeck    = "french"
+	PiquetDeck    = "piquet"
+	EuchreDeck    = "euchre"
+	PinochleDeck  = "pinochle"
+	Spanish40Deck = "spanish40"
+	Spanish48Deck = "spanish48"
+	TarotDeck     = "tarot"
+)
+
+// MajorArcana holds names of the tarot trumps from 0 to 21, coded from 0M to 21M
+var MajorArcana = []string{
+	"THE FOOL", "THE MAGICIAN", "THE HIGH PRIESTESS", "THE EMPRESS", "THE EMPEROR", "THE HIEROPHANT", "THE LOVERS",
+	"THE CHARIOT", "STRENGTH", "THE HERMIT", "WHEEL OF FORTUNE", "JUSTICE", "THE HANGED MAN", "DEATH", "TEMPERANCE",
+	"THE DEVIL", "THE TOWER", "THE STAR", "THE MOON", "THE SUN", "JUDGEMENT", "THE WORLD",
+}
+
+// DeckType defines a kind of deck by its cards in canonical order. Codes of the cards are the valid codes of a partial
+// deck of the type. Suits and ranks, if given, tell how codes are made up: a rank code followed by a suit code.
+type DeckType struct {
+	Name  string   `json:"name"`
+	Suits []Symbol `json:"suits,omitempty"`
+	Ranks []Symbol `json:"ranks,omitempty"`
+	Cards []Card   `json:"cards"`
+}
+
+var ErrInvalidDeckType = errors.New("invalid deck type")
+
+var (
+	deckTypesMu sync.RWMutex
+	deckTypes   = make(map[string]DeckType)
+)
+
+func init() {
+	frenchSuits := make([]Symbol, 0, len(card.Suits))
+	for _, s := range card.Suits {
+		frenchSuits = append(frenchSuits, Symbol{Code: s.Code(), Name: s.String()})
+	}
+	frenchDeck := func(name string, ranks []card.Rank, copies int) DeckType {
+		t := DeckType{Name: name, Suits: frenchSuits}
+		for _, rank := range ranks {
+			t.Ranks = append(t.Ranks, Symbol{Code: rank.Code(), Name: rank.String()})
+		}
+
+		for _, s := range card.Suits {
+			for _, rank := range ranks {
+				c := card.Card{Rank: rank, Suit: s}
+				// copies of a card are told apart by Copy, from 1, a card the type has once has none
+				for i := 1; i <= copies; i++ {
+					tc := Card{Code: c.Code(), Value: c.Value(), Suit: s.String()}
+					if 1 < copies {
+						tc.Copy = i
+					}
+					t.Cards = append(t.Cards, tc)
+				}
+			}
+		}
+
+		return t
+	}
+	euchreRanks := []card.Rank{card.Ace, card.Nine, card.Ten, card.Jack, card.Queen, card.King}
+
+	spanishSuits := []Symbol{{Code: "O", Name: "COINS"}, {Code: "C", Name: "CUPS"}, {Code: "E", Name: "SWORDS"}, {Code: "B", Name: "CLUBS"}}
+	spanishValues := map[int]string{1: "ACE", 10: "JACK", 11: "KNIGHT", 12: "KING"}
+	spanishDeck := func(name string, ranks []int) DeckType {
+		t := DeckType{Name: name, Suits: spanishSuits}
+		for _, rank := range ranks {
+			value, ok := spanishValues[rank]
+			if !ok {
+				value = strconv.Itoa(rank)
+			}
+
+			t.Ranks = append(t.Ranks, Symbol{Code: strconv.Itoa(rank), Name: value})
+		}
+
+		for _, s := range spanishSuits {
+			for _, rank := range t.Ranks {
+				t.Cards = append(t.Cards, Card{Code: rank.Code + s.Code, Value: rank.Name, Suit: s.Name})
+			}
+		}
+
+		return t
+	}
+
+	// trumps are ranked by their numbers in the major suit, minor suits have numbers from 2 to 10 as well
+	tarotDeck := func() DeckType {
+		t := DeckType{Name: TarotDeck, Suits: []Symbol{{Code: "M", Name: "MAJOR"}}}
+		for i, name := range MajorArcana {
+			t.Ranks = append(t.Ranks, Symbol{Code: strconv.Itoa(i), Name: strconv.Itoa(i)})
+			t.Cards = append(t.Cards, Card{Code: strconv.Itoa(i) + "M", Value: name, Suit: "MAJOR"})
+		}
+
+		ace := Symbol{Code: "A", Name: "ACE"}
+		courts := []Symbol{{Code: "P", Name: "PAGE"}, {Code: "N", Name: "KNIGHT"}, {Code: "Q", Name: "QUEEN"}, {Code: "K", Name: "KING"}}
+		minorRanks := []Symbol{ace}
+		for rank := 2; rank <= 10; rank++ {
+			minorRanks = append(minorRanks, Symbol{Code: strconv.Itoa(rank), Name: strconv.Itoa(rank)})
+		}
+		minorRanks = append(minorRanks, courts...)
+
+		// ranks 2 to 10 share their codes with the trumps
+		t.Ranks = append(append(t.Ranks, ace), courts...)
+
+		for _, s := range []Symbol{{Code: "W", Name: "WANDS"}, {Code: "C", Name: "CUPS"}, {Code: "S", Name: "SWORDS"}, {Code: "P", Name: "PENTACLES"}} {
+			t.Suits = append(t.Suits, s)
+			for _, rank := range minorRanks {
+				t.Cards = append(t.Cards, Card{Code: rank.Code + s.Code, Value: rank.Name, Suit: s.Name})
+			}
+		}
+
+		return t
+	}
+
+	for _, t := range []DeckType{
+		frenchDeck(FrenchDeck, card.Ranks, 1),
+		frenchDeck(PiquetDeck, append([]card.Rank{card.Ace}, card.Ranks[card.Seven-1:]...), 1),
+		frenchDeck(EuchreDeck, euchreRanks, 1),
+		frenchDeck(PinochleDeck, euchreRanks, 2),
+		spanishDeck(Spanish40Deck, []int{1, 2, 3, 4, 5, 6, 7, 10, 11, 12}),
+		spanishDeck(Spanish48Deck, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}),
+		tarotDeck(),
+	} {
+		if err := RegisterDeckType(t); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterDeckType makes a deck type available by its name. If the name is empty or taken, or the type has no
+// cards, ErrInvalidDeckType is returned.
+func RegisterDeckType(t DeckType) error {
+	deckTypesMu.Lock()
+	defer deckTypesMu.Unlock()
+
+	if "" == t.Name || 0 == len(t.Cards) {
+		return fmt.Errorf("%w: %q needs a name and cards", ErrInvalidDeckType, t.Name)
+	}
+
+	if _, ok := deckTypes[t.Name]; ok {
+		return fmt.Errorf("%w: %q is already registered", ErrInvalidDeckType, t.Name)
+	}
+
+	deckTypes[t.Name] = t.copy()
+
+	return nil
+}
+
+// LookupDeckType returns the deck type registered with name, its cards can be modified freely
+func LookupDeckType(name string) (DeckType, bool) {
+	deckTypesMu.RLock()
+	defer deckTypesMu.RUnlock()
+
+	t, ok := deckTypes[name]
+	if !ok {
+		return DeckType{}, false
+	}
+
+	return t.copy(), true
+}
+
+// DeckTypeNames returns names of the registered deck types in alphabetical order
+func DeckTypeNames() []string {
+	deckTypesMu.RLock()
+	defer deckTypesMu.RUnlock()
+
+	var names []string
+	for name := range deckTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// copy returns a copy of the type that shares nothing with t
+func (t DeckType) copy() DeckType {
+	return DeckType{
+		Name:  t.Name,
+		Suits: append([]Symbol(nil), t.Suits...),
+		Ranks: append([]Symbol(nil), t.Ranks...),
+		Cards: append([]Card(nil), t.Cards...),
+	}
+}
+
+// find returns the card of the type with given code. French cards are found by any code card.Parse accepts, e.g.
+// th for 10H, as long as the type has no card with the code as it is.
+func (t DeckType) find(code string) (Card, bool) {
+	code = strings.TrimSpace(code)
+	for _, candidate := range []string{code, card.Canonical(code)} {
+		for _, c := range t.Cards {
+			if candidate == c.Code {
+				return c, true
+			}
+		}
+	}
+
+	return Card{}, false
+}
@@ -0,0 +1,100 @@
+package creating
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLookupDeckType(t *testing.T) {
+	tests := []struct {
+		name  string
+		total int
+		first string
+		last  string
+	}{
+		{name: FrenchDeck, total: 52, first: "AS", last: "KH"},
+		{name: PiquetDeck, total: 32, first: "AS", last: "KH"},
+		{name: EuchreDeck, total: 24, first: "AS", last: "KH"},
+		{name: PinochleDeck, total: 48, first: "AS", last: "KH"},
+		{name: Spanish40Deck, total: 40, first: "1O", last: "12B"},
+		{name: Spanish48Deck, total: 48, first: "1O", last: "12B"},
+		{name: TarotDeck, total: 78, first: "0M", last: "KP"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := LookupDeckType(tt.name)
+			if !ok {
+				t.Fatalf("LookupDeckType() %s is not registered", tt.name)
+			}
+
+			if n := len(got.Cards); tt.total != n || tt.first != got.Cards[0].Code || tt.last != got.Cards[n-1].Code {
+				t.Errorf("LookupDeckType() got %d cards from %s to %s, want %d from %s to %s",
+					n, got.Cards[0].Code, got.Cards[n-1].Code, tt.total, tt.first, tt.last)
+			}
+
+			// cards are copies, changing them must not change the registry
+			got.Cards[0].Code = "changed"
+			if again, _ := LookupDeckType(tt.name); tt.first != again.Cards[0].Code {
+				t.Errorf("LookupDeckType() registry changed to %s", again.Cards[0].Code)
+			}
+		})
+	}
+
+	if _, ok := LookupDeckType("hanafuda"); ok {
+		t.Errorf("LookupDeckType() found unregistered type")
+	}
+}
+
+func TestLookupDeckType_pinochleCopies(t *testing.T) {
+	pinochle, _ := LookupDeckType(PinochleDeck)
+
+	type key struct {
+		code string
+		copy int
+	}
+	seen := make(map[key]int)
+	codes := make(map[string]bool)
+	for _, c := range pinochle.Cards {
+		seen[key{c.Code, c.Copy}]++
+		codes[c.Code] = true
+	}
+
+	if 48 != len(seen) || 24 != len(codes) {
+		t.Fatalf("LookupDeckType() got %d distinct (code, copy) of %d codes, want 48 of 24", len(seen), len(codes))
+	}
+
+	for code := range codes {
+		if 1 != seen[key{code, 1}] || 1 != seen[key{code, 2}] {
+			t.Errorf("LookupDeckType() %s copies = %d and %d, want one of each", code, seen[key{code, 1}], seen[key{code, 2}])
+		}
+	}
+}
+
+func TestRegisterDeckType(t *testing.T) {
+	tests := []struct {
+		name    string
+		t       DeckType
+		wantErr error
+	}{
+		{name: "no name", t: DeckType{Cards: []Card{{Code: "AS"}}}, wantErr: ErrInvalidDeckType},
+		{name: "no cards", t: DeckType{Name: "empty"}, wantErr: ErrInvalidDeckType},
+		{name: "taken", t: DeckType{Name: FrenchDeck, Cards: []Card{{Code: "AS"}}}, wantErr: ErrInvalidDeckType},
+		{name: "valid", t: DeckType{Name: "aces", Cards: []Card{{Code: "AS", Value: "ACE", Suit: "SPADES"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RegisterDeckType(tt.t); !errors.Is(err, tt.wantErr) {
+				t.Errorf("RegisterDeckType() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	found := false
+	for _, name := range DeckTypeNames() {
+		found = found || "aces" == name
+	}
+
+	if !found {
+		t.Errorf("DeckTypeNames() = %v, want aces included", DeckTypeNames())
+	}
+}
@@ -0,0 +1,128 @@
+package creating
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Limits of a custom deck definition, codes and names of the cards must fit the cards table
+const (
+	MaxDefinitionNameLength = 40
+	MaxDefinitionCards      = 200
+	MaxCardCodeLength       = 3
+	MaxCardNameLength       = 10
+)
+
+type (
+	// Definition describes a custom deck type. The deck has every rank of every suit, suit by suit in the given order,
+	// each card coded as its rank code followed by its suit code. Counts sets how many times a card is in the deck by
+	// its code, 0 leaves the card out. Cards without a count are in the deck once.
+	Definition struct {
+		Name   string         `json:"name"`
+		Suits  []Symbol       `json:"suits"`
+		Ranks  []Symbol       `json:"ranks"`
+		Counts map[string]int `json:"counts,omitempty"`
+	}
+
+	// Symbol is a suit or a rank of a Definition with its code and display name
+	Symbol struct {
+		Code string `json:"code"`
+		Name string `json:"name"`
+	}
+)
+
+var ErrInvalidDefinition = errors.New("invalid deck definition")
+var ErrDefinitionExists = errors.New("deck definition already exists")
+var ErrDefinitionNotFound = errors.New("deck definition not found")
+
+// DeckType validates the definition and returns the deck type it describes. In case the definition is not valid,
+// ErrInvalidDefinition is returned.
+func (def Definition) DeckType() (DeckType, error) {
+	invalid := func(format string, a ...interface{}) (DeckType, error) {
+		return DeckType{}, fmt.Errorf("%w: %s", ErrInvalidDefinition, fmt.Sprintf(format, a...))
+	}
+
+	if !validName(def.Name) {
+		return invalid("name %q must be 1 to %d lower case letters, digits, - or _", def.Name, MaxDefinitionNameLength)
+	}
+
+	if 0 == len(def.Suits) || 0 == len(def.Ranks) {
+		return invalid("suits and ranks can not be empty")
+	}
+
+	for _, symbols := range [][]Symbol{def.Suits, def.Ranks} {
+		seen := make(map[string]bool)
+		for _, s := range symbols {
+			if "" == s.Code || strings.ContainsAny(s.Code, ", \t\r\n") || seen[s.Code] {
+				return invalid("code %q is empty, repeated or has commas or spaces", s.Code)
+			}
+			seen[s.Code] = true
+
+			if "" == strings.TrimSpace(s.Name) || MaxCardNameLength < len(s.Name) {
+				return invalid("name %q of %s must be 1 to %d characters", s.Name, s.Code, MaxCardNameLength)
+			}
+		}
+	}
+
+	t := DeckType{Name: def.Name, Suits: def.Suits, Ranks: def.Ranks}
+	counted := make(map[string]bool)
+	for _, suit := range def.Suits {
+		for _, rank := range def.Ranks {
+			code := rank.Code + suit.Code
+			if _, ok := t.find(code); ok || counted[code] {
+				return invalid("code %s is ambiguous", code)
+			}
+
+			if MaxCardCodeLength < len(code) || isJoker(code) {
+				return invalid("code %s must be at most %d characters and not a joker", code, MaxCardCodeLength)
+			}
+
+			n, ok := def.Counts[code]
+			if !ok {
+				n = 1
+			}
+			counted[code] = true
+
+			if 0 > n || MaxDefinitionCards < len(t.Cards)+n {
+				return invalid("count of %s is negative or the deck has more than %d cards", code, MaxDefinitionCards)
+			}
+
+			// copies of a card are told apart by Copy, from 1, like in built-in types
+			for i := 1; i <= n; i++ {
+				c := Card{Code: code, Value: rank.Name, Suit: suit.Name}
+				if 1 < n {
+					c.Copy = i
+				}
+				t.Cards = append(t.Cards, c)
+			}
+		}
+	}
+
+	for code := range def.Counts {
+		if !counted[code] {
+			return invalid("count of unknown card %s", code)
+		}
+	}
+
+	if 0 == len(t.Cards) {
+		return invalid("deck has no cards")
+	}
+
+	return t, nil
+}
+
+// validName reports if name is usable as a deck type name
+func validName(name string) bool {
+	if "" == name || MaxDefinitionNameLength < len(name) {
+		return false
+	}
+
+	for _, r := range name {
+		if !('a' <= r && 'z' >= r) && !('0' <= r && '9' >= r) && '-' != r && '_' != r {
+			return false
+		}
+	}
+
+	return true
+}
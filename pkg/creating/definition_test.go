@@ -0,0 +1,156 @@
+package creating
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDefinition_DeckType(t *testing.T) {
+	suits := []Symbol{{Code: "R", Name: "RED"}, {Code: "B", Name: "BLUE"}}
+	ranks := []Symbol{{Code: "1", Name: "ONE"}, {Code: "W", Name: "WILD"}}
+
+	tests := []struct {
+		name    string
+		def     Definition
+		want    DeckType
+		wantErr error
+	}{
+		{
+			name: "valid",
+			def:  Definition{Name: "colors", Suits: suits, Ranks: ranks},
+			want: DeckType{Name: "colors", Suits: suits, Ranks: ranks, Cards: []Card{
+				{Code: "1R", Value: "ONE", Suit: "RED"},
+				{Code: "WR", Value: "WILD", Suit: "RED"},
+				{Code: "1B", Value: "ONE", Suit: "BLUE"},
+				{Code: "WB", Value: "WILD", Suit: "BLUE"},
+			}},
+		},
+		{
+			name: "counts",
+			def:  Definition{Name: "colors", Suits: suits, Ranks: ranks, Counts: map[string]int{"WR": 0, "WB": 3}},
+			want: DeckType{Name: "colors", Suits: suits, Ranks: ranks, Cards: []Card{
+				{Code: "1R", Value: "ONE", Suit: "RED"},
+				{Code: "1B", Value: "ONE", Suit: "BLUE"},
+				{Code: "WB", Value: "WILD", Suit: "BLUE", Copy: 1},
+				{Code: "WB", Value: "WILD", Suit: "BLUE", Copy: 2},
+				{Code: "WB", Value: "WILD", Suit: "BLUE", Copy: 3},
+			}},
+		},
+		{name: "no name", def: Definition{Suits: suits, Ranks: ranks}, wantErr: ErrInvalidDefinition},
+		{name: "upper case name", def: Definition{Name: "Colors", Suits: suits, Ranks: ranks}, wantErr: ErrInvalidDefinition},
+		{name: "no suits", def: Definition{Name: "colors", Ranks: ranks}, wantErr: ErrInvalidDefinition},
+		{
+			name:    "repeated rank",
+			def:     Definition{Name: "colors", Suits: suits, Ranks: []Symbol{{Code: "1", Name: "ONE"}, {Code: "1", Name: "UNO"}}},
+			wantErr: ErrInvalidDefinition,
+		},
+		{
+			name:    "comma in code",
+			def:     Definition{Name: "colors", Suits: []Symbol{{Code: ",", Name: "COMMA"}}, Ranks: ranks},
+			wantErr: ErrInvalidDefinition,
+		},
+		{
+			name:    "long display name",
+			def:     Definition{Name: "colors", Suits: []Symbol{{Code: "R", Name: "REDDISH ORANGE"}}, Ranks: ranks},
+			wantErr: ErrInvalidDefinition,
+		},
+		{
+			name: "ambiguous codes",
+			def: Definition{
+				Name:  "colors",
+				Suits: []Symbol{{Code: "1R", Name: "RED"}, {Code: "R", Name: "ROSE"}},
+				Ranks: []Symbol{{Code: "1", Name: "ONE"}, {Code: "11", Name: "ELEVEN"}},
+			},
+			wantErr: ErrInvalidDefinition,
+		},
+		{
+			name:    "long code",
+			def:     Definition{Name: "colors", Suits: suits, Ranks: []Symbol{{Code: "100", Name: "HUNDRED"}}},
+			wantErr: ErrInvalidDefinition,
+		},
+		{
+			name:    "joker code",
+			def:     Definition{Name: "colors", Suits: []Symbol{{Code: "1", Name: "ONE"}}, Ranks: []Symbol{{Code: "X", Name: "X"}}},
+			wantErr: ErrInvalidDefinition,
+		},
+		{
+			name:    "unknown count",
+			def:     Definition{Name: "colors", Suits: suits, Ranks: ranks, Counts: map[string]int{"2R": 1}},
+			wantErr: ErrInvalidDefinition,
+		},
+		{
+			name:    "negative count",
+			def:     Definition{Name: "colors", Suits: suits, Ranks: ranks, Counts: map[string]int{"1R": -1}},
+			wantErr: ErrInvalidDefinition,
+		},
+		{
+			name:    "too many cards",
+			def:     Definition{Name: "colors", Suits: suits, Ranks: ranks, Counts: map[string]int{"1R": MaxDefinitionCards}},
+			wantErr: ErrInvalidDefinition,
+		},
+		{
+			name:    "no cards",
+			def:     Definition{Name: "colors", Suits: suits[:1], Ranks: ranks, Counts: map[string]int{"1R": 0, "WR": 0}},
+			wantErr: ErrInvalidDefinition,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.def.DeckType()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("DeckType() error = %v, want %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DeckType() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_service_CreateDefinition(t *testing.T) {
+	def := Definition{
+		Name:  "colors",
+		Suits: []Symbol{{Code: "R", Name: "RED"}, {Code: "B", Name: "BLUE"}},
+		Ranks: []Symbol{{Code: "1", Name: "ONE"}, {Code: "2", Name: "TWO"}},
+	}
+
+	tests := []struct {
+		name    string
+		r       *mockDB
+		def     Definition
+		wantErr error
+	}{
+		{name: "valid", r: &mockDB{}, def: def},
+		{name: "invalid", r: &mockDB{}, def: Definition{Name: "colors"}, wantErr: ErrInvalidDefinition},
+		{name: "built-in", r: &mockDB{}, def: Definition{Name: PiquetDeck, Suits: def.Suits, Ranks: def.Ranks}, wantErr: ErrDefinitionExists},
+		{name: "stored", r: &mockDB{types: map[string]DeckType{"colors": {Name: "colors"}}}, def: def, wantErr: ErrDefinitionExists},
+		{name: "db fail", r: &mockDB{err: errors.New("db fail")}, def: def, wantErr: ErrCreate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{r: tt.r}
+			got, err := s.CreateDefinition(tt.def)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CreateDefinition() error = %v, want %v", err, tt.wantErr)
+				return
+			}
+
+			if nil != tt.wantErr {
+				return
+			}
+
+			// decks of the stored definition only accept its cards
+			deck, err := s.CreateDeck(Deck{Type: "colors", Remaining: FrenchDeckCardTotal})
+			if err != nil || !reflect.DeepEqual(got.Cards, deck.Cards) || 4 != deck.Remaining {
+				t.Errorf("CreateDeck() got = %v, error = %v, want cards %v", deck, err, got.Cards)
+			}
+
+			if _, err := s.CreateDeck(Deck{Type: "colors", Remaining: 1, Cards: []Card{{Code: "AS"}}}); !errors.As(err, &ErrInvalidCard) {
+				t.Errorf("CreateDeck() error = %v, want %T", err, ErrInvalidCard)
+			}
+		})
+	}
+}
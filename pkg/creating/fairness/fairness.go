@@ -0,0 +1,167 @@
+// Package fairness measures how uniform the orders given by a creating.Shuffler are.
+//
+// A shuffler is run many times on a deck of cards 0..n-1 and two statistics are collected:
+//   - position: how many times each card ends up at each position
+//   - adjacency: how many times each card is directly followed by each other card
+//
+// For a uniform shuffler every cell of both is expected to be hit equally often. Deviation is reported as
+// a chi-square statistic, converted to a z-score with Wilson–Hilferty approximation so that one threshold
+// works for any deck size.
+package fairness
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/srgyrn/lucky-38/pkg/creating"
+)
+
+// DefaultMaxZ fails a statistic once it is less likely than about 3 in 100.000 for a uniform shuffler
+const DefaultMaxZ = 4.0
+
+var ErrInvalidRun = errors.New("invalid fairness run")
+var ErrBiased = errors.New("shuffler is biased")
+
+type (
+	// Report holds the statistics of a run
+	Report struct {
+		Size     int
+		Shuffles int
+
+		// Positions[card][position] counts how many times card ended up at position
+		Positions [][]int
+		// Adjacency[a][b] counts how many times card b directly followed card a
+		Adjacency [][]int
+
+		Position Statistic
+		Adjacent Statistic
+	}
+
+	// Statistic sums up deviation of the observed counts from the expected ones
+	Statistic struct {
+		ChiSquare        float64
+		DegreesOfFreedom int
+		Z                float64
+		// MaxBias is the highest relative deviation of a cell from its expected count, i.e. |observed-expected|/expected
+		MaxBias float64
+	}
+)
+
+// Run shuffles a deck of size cards with s the given number of times and reports the statistics.
+func Run(s creating.Shuffler, size, shuffles int) (Report, error) {
+	if 2 > size || 1 > shuffles {
+		return Report{}, fmt.Errorf("%w: size %d, shuffles %d", ErrInvalidRun, size, shuffles)
+	}
+
+	report := Report{
+		Size:      size,
+		Shuffles:  shuffles,
+		Positions: matrix(size),
+		Adjacency: matrix(size),
+	}
+
+	deck := make([]int, size)
+	for n := 0; n < shuffles; n++ {
+		for i := range deck {
+			deck[i] = i
+		}
+
+		err := s.Shuffle(size, func(i, j int) {
+			deck[i], deck[j] = deck[j], deck[i]
+		})
+		if err != nil {
+			return Report{}, fmt.Errorf("error at shuffle %d: %v", n, err)
+		}
+
+		for position, card := range deck {
+			report.Positions[card][position]++
+			if 0 < position {
+				report.Adjacency[deck[position-1]][card]++
+			}
+		}
+	}
+
+	// every card is expected at every position shuffles/size times. Each cell is binomial, its scaled deviation
+	// averages to 1-1/size, so the statistic averages to size*(size-1)
+	report.Position = statistic(report.Positions, float64(shuffles)/float64(size), size*(size-1), false)
+
+	// each of size-1 adjacent slots holds one of size*(size-1) ordered pairs, so every pair is expected
+	// shuffles/size times. Cards can not follow themselves, the diagonal is left out and the statistic
+	// averages to (size-1)^2
+	report.Adjacent = statistic(report.Adjacency, float64(shuffles)/float64(size), (size-1)*(size-1), true)
+
+	return report, nil
+}
+
+// Check returns ErrBiased if z-score of any statistic exceeds maxZ
+func (r Report) Check(maxZ float64) error {
+	for _, s := range []struct {
+		name string
+		Statistic
+	}{{"position", r.Position}, {"adjacency", r.Adjacent}} {
+		if s.Z > maxZ {
+			return fmt.Errorf("%w: %s z-score %.2f exceeds %.2f", ErrBiased, s.name, s.Z, maxZ)
+		}
+	}
+
+	return nil
+}
+
+// String sums up the report, followed by the position bias matrix in percentages of the expected count
+func (r Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d shuffles of %d cards\n", r.Shuffles, r.Size)
+	fmt.Fprintf(&b, "position:  %s\n", r.Position)
+	fmt.Fprintf(&b, "adjacency: %s\n", r.Adjacent)
+
+	expected := float64(r.Shuffles) / float64(r.Size)
+	b.WriteString("position bias (%), card per row, position per column:\n")
+	for _, row := range r.Positions {
+		for i, observed := range row {
+			if 0 < i {
+				b.WriteByte(' ')
+			}
+			fmt.Fprintf(&b, "%+6.2f", 100*(float64(observed)-expected)/expected)
+		}
+		b.WriteByte('\n')
+	}
+
+	return b.String()
+}
+
+func (s Statistic) String() string {
+	return fmt.Sprintf("chi-square %.2f, df %d, z %.2f, max bias %.2f%%", s.ChiSquare, s.DegreesOfFreedom, s.Z, 100*s.MaxBias)
+}
+
+// statistic compares counts to expected, skipping the diagonal if asked
+func statistic(counts [][]int, expected float64, df int, skipDiagonal bool) Statistic {
+	s := Statistic{DegreesOfFreedom: df}
+	for i, row := range counts {
+		for j, observed := range row {
+			if skipDiagonal && i == j {
+				continue
+			}
+
+			deviation := float64(observed) - expected
+			s.ChiSquare += deviation * deviation / expected
+			s.MaxBias = math.Max(s.MaxBias, math.Abs(deviation)/expected)
+		}
+	}
+
+	// Wilson–Hilferty: (X/k)^(1/3) is approximately normal with mean 1-2/(9k) and variance 2/(9k)
+	k := float64(df)
+	s.Z = (math.Cbrt(s.ChiSquare/k) - (1 - 2/(9*k))) / math.Sqrt(2/(9*k))
+
+	return s
+}
+
+func matrix(size int) [][]int {
+	m := make([][]int, size)
+	for i := range m {
+		m[i] = make([]int, size)
+	}
+
+	return m
+}
@@ -0,0 +1,118 @@
+package fairness_test
+
+import (
+	"errors"
+	"flag"
+	"math/rand"
+	"testing"
+
+	"github.com/srgyrn/lucky-38/pkg/creating"
+	"github.com/srgyrn/lucky-38/pkg/creating/fairness"
+)
+
+// run with -shuffles 1000000 -v for an audit report
+var shuffles = flag.Int("shuffles", 20000, "number of shuffles per fairness test")
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		shuffler   creating.Shuffler
+		wantBiased bool
+	}{
+		{name: "crypto", shuffler: creating.CryptoShuffler{}},
+		{name: "prng", shuffler: creating.NewPRNGShuffler(38)},
+		{name: "biased", shuffler: &biasedShuffler{rnd: rand.New(rand.NewSource(38))}, wantBiased: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := fairness.Run(tt.shuffler, creating.FrenchDeckCardTotal, *shuffles)
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			t.Log(report.Position, report.Adjacent)
+			if testing.Verbose() {
+				t.Log(report)
+			}
+
+			err = report.Check(fairness.DefaultMaxZ)
+			if tt.wantBiased != errors.Is(err, fairness.ErrBiased) {
+				t.Errorf("Check() error = %v, want biased %v", err, tt.wantBiased)
+			}
+		})
+	}
+}
+
+func TestRun_counts(t *testing.T) {
+	report, err := fairness.Run(creating.NewPRNGShuffler(38), 4, 100)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	for card, row := range report.Positions {
+		var total int
+		for _, n := range row {
+			total += n
+		}
+
+		if 100 != total {
+			t.Errorf("card %d counted at %d positions, want 100", card, total)
+		}
+	}
+
+	var pairs int
+	for a, row := range report.Adjacency {
+		for b, n := range row {
+			if a == b && 0 != n {
+				t.Errorf("card %d followed itself %d times", a, n)
+			}
+			pairs += n
+		}
+	}
+
+	if 300 != pairs {
+		t.Errorf("counted %d adjacent pairs, want 300", pairs)
+	}
+}
+
+func TestRun_invalid(t *testing.T) {
+	for _, args := range [][2]int{{1, 100}, {52, 0}} {
+		if _, err := fairness.Run(creating.CryptoShuffler{}, args[0], args[1]); !errors.Is(err, fairness.ErrInvalidRun) {
+			t.Errorf("Run(%d, %d) error = %v, want %v", args[0], args[1], err, fairness.ErrInvalidRun)
+		}
+	}
+}
+
+func BenchmarkCryptoShuffler(b *testing.B) {
+	benchmarkShuffler(b, creating.CryptoShuffler{})
+}
+
+func BenchmarkPRNGShuffler(b *testing.B) {
+	benchmarkShuffler(b, creating.NewPRNGShuffler(38))
+}
+
+func benchmarkShuffler(b *testing.B, s creating.Shuffler) {
+	deck := make([]int, creating.FrenchDeckCardTotal)
+	swap := func(i, j int) {
+		deck[i], deck[j] = deck[j], deck[i]
+	}
+
+	for i := 0; i < b.N; i++ {
+		if err := s.Shuffle(len(deck), swap); err != nil {
+			b.Fatalf("Shuffle() error = %v", err)
+		}
+	}
+}
+
+// biasedShuffler swaps every card with any card of the deck, which favours some orders over the others
+type biasedShuffler struct {
+	rnd *rand.Rand
+}
+
+func (bs *biasedShuffler) Shuffle(n int, swap func(i, j int)) error {
+	for i := 0; i < n; i++ {
+		swap(i, bs.rnd.Intn(n))
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package creating
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Filter derives the cards of a partial deck from the full deck of its type. A card is kept if its suit code is one of
+// Suits, its rank code is one of Ranks, its rank is at least MinRank and it is not one of Exclude. Empty fields keep
+// every card. Ranks that are not numbers, e.g. aces and faces, are above every number.
+type Filter struct {
+	Exclude []string
+	Suits   []string
+	Ranks   []string
+	MinRank int
+}
+
+// empty reports if the filter keeps every card
+func (f Filter) empty() bool {
+	return 0 == len(f.Exclude) && 0 == len(f.Suits) && 0 == len(f.Ranks) && 0 == f.MinRank
+}
+
+// filter returns the cards of t that f keeps in canonical order. If f has unknown suits or ranks, t has no suits and
+// ranks to filter by, or no card is left, ErrInvalidDeck is returned. If any of the excluded codes is not a card of t,
+// ErrInvalidCard is returned.
+func (t DeckType) filter(f Filter) ([]Card, error) {
+	if 0 > f.MinRank {
+		return nil, fmt.Errorf("%w: min rank %d is negative", ErrInvalidDeck, f.MinRank)
+	}
+
+	bySymbols := 0 < len(f.Suits) || 0 < len(f.Ranks) || 0 < f.MinRank
+	if bySymbols && (0 == len(t.Suits) || 0 == len(t.Ranks)) {
+		return nil, fmt.Errorf("%w: %s decks can not be filtered by suit or rank", ErrInvalidDeck, t.Name)
+	}
+
+	suits, err := symbolSet("suit", f.Suits, t.Suits)
+	if err != nil {
+		return nil, err
+	}
+
+	ranks, err := symbolSet("rank", f.Ranks, t.Ranks)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := make(map[string]bool)
+	for _, code := range f.Exclude {
+		c, ok := t.find(code)
+		if !ok {
+			return nil, &InvalidCardErr{Card{Code: code}}
+		}
+		excluded[c.Code] = true
+	}
+
+	var cards []Card
+	for _, c := range t.Cards {
+		if excluded[c.Code] {
+			continue
+		}
+
+		if bySymbols {
+			rank, suit, ok := t.symbols(c.Code)
+			if !ok || (0 < len(suits) && !suits[suit.Code]) || (0 < len(ranks) && !ranks[rank.Code]) {
+				continue
+			}
+
+			if n, err := strconv.Atoi(rank.Code); err == nil && n < f.MinRank {
+				continue
+			}
+		}
+
+		cards = append(cards, c)
+	}
+
+	if 0 == len(cards) {
+		return nil, fmt.Errorf("%w: no cards left after filtering", ErrInvalidDeck)
+	}
+
+	return cards, nil
+}
+
+// symbols returns the rank and the suit that code is made of
+func (t DeckType) symbols(code string) (Symbol, Symbol, bool) {
+	for _, suit := range t.Suits {
+		for _, rank := range t.Ranks {
+			if code == rank.Code+suit.Code {
+				return rank, suit, true
+			}
+		}
+	}
+
+	return Symbol{}, Symbol{}, false
+}
+
+// symbolSet returns the codes of symbols matching given codes in any case. If any of the codes is not one of the
+// symbols, ErrInvalidDeck is returned.
+func symbolSet(kind string, codes []string, symbols []Symbol) (map[string]bool, error) {
+	set := make(map[string]bool)
+	for _, code := range codes {
+		found := false
+		for _, s := range symbols {
+			if strings.EqualFold(strings.TrimSpace(code), s.Code) {
+				set[s.Code], found = true, true
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("%w: unknown %s %q", ErrInvalidDeck, kind, code)
+		}
+	}
+
+	return set, nil
+}
@@ -0,0 +1,98 @@
+package creating
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDeckType_filter(t *testing.T) {
+	french, _ := LookupDeckType(FrenchDeck)
+	euchre, _ := LookupDeckType(EuchreDeck)
+	tarot, _ := LookupDeckType(TarotDeck)
+	pinochle, _ := LookupDeckType(PinochleDeck)
+	codes := func(cards []Card) []string {
+		var result []string
+		for _, c := range cards {
+			result = append(result, c.Code)
+		}
+
+		return result
+	}
+
+	tests := []struct {
+		name    string
+		t       DeckType
+		f       Filter
+		want    []string
+		wantErr error
+	}{
+		{name: "min rank", t: french, f: Filter{MinRank: 9}, want: codes(euchre.Cards)},
+		{
+			name: "suits and ranks",
+			t:    french,
+			f:    Filter{Suits: []string{"h", "S"}, Ranks: []string{"A", "k", "10"}},
+			want: []string{"AS", "10S", "KS", "AH", "10H", "KH"},
+		},
+		{
+			name: "exclude",
+			t:    french,
+			f:    Filter{Suits: []string{"D"}, Exclude: []string{"2D", "td", "Q♦"}},
+			want: []string{"AD", "3D", "4D", "5D", "6D", "7D", "8D", "9D", "JD", "KD"},
+		},
+		{name: "exclude every copy", t: pinochle, f: Filter{Suits: []string{"H"}, Ranks: []string{"9", "10"}, Exclude: []string{"9H"}}, want: []string{"10H", "10H"}},
+		{name: "trumps", t: tarot, f: Filter{Suits: []string{"M"}, MinRank: 20}, want: []string{"20M", "21M"}},
+		{name: "unknown suit", t: french, f: Filter{Suits: []string{"Z"}}, wantErr: ErrInvalidDeck},
+		{name: "unknown rank", t: french, f: Filter{Ranks: []string{"1"}}, wantErr: ErrInvalidDeck},
+		{name: "unknown excluded card", t: french, f: Filter{Exclude: []string{"1S"}}, wantErr: ErrInvalidCard},
+		{name: "negative min rank", t: french, f: Filter{MinRank: -1}, wantErr: ErrInvalidDeck},
+		{name: "no cards left", t: french, f: Filter{Suits: []string{"S"}, MinRank: 14, Exclude: []string{"AS", "JS", "QS", "KS"}}, wantErr: ErrInvalidDeck},
+		{
+			name:    "no symbols",
+			t:       DeckType{Name: "plain", Cards: []Card{{Code: "AS"}}},
+			f:       Filter{Suits: []string{"S"}},
+			wantErr: ErrInvalidDeck,
+		},
+		{name: "no symbols/exclude", t: DeckType{Name: "plain", Cards: []Card{{Code: "AS"}, {Code: "KS"}}}, f: Filter{Exclude: []string{"AS"}}, want: []string{"KS"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.t.filter(tt.f)
+			if !errors.Is(err, tt.wantErr) && reflect.TypeOf(err) != reflect.TypeOf(tt.wantErr) {
+				t.Errorf("filter() error = %v, want %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(codes(got), tt.want) {
+				t.Errorf("filter() got = %v, want %v", codes(got), tt.want)
+			}
+		})
+	}
+}
+
+func Test_service_CreateDeck_filtered(t *testing.T) {
+	s := NewService(&mockDB{})
+
+	got, err := s.CreateDeck(Deck{Remaining: FrenchDeckCardTotal, Filter: Filter{Suits: []string{"H"}}})
+	if err != nil {
+		t.Fatalf("CreateDeck() error = %v", err)
+	}
+
+	if 13 != got.Remaining || 13 != len(got.Cards) || "AH" != got.Cards[0].Code {
+		t.Errorf("CreateDeck() = %v, want the hearts", got)
+	}
+
+	got, err = s.CreateDeck(Deck{Remaining: FrenchDeckCardTotal, Jokers: 2, Filter: Filter{Suits: []string{"H"}}})
+	if err != nil {
+		t.Fatalf("CreateDeck() error = %v", err)
+	}
+
+	if 15 != got.Remaining || "X1" != got.Cards[13].Code || "X2" != got.Cards[14].Code {
+		t.Errorf("CreateDeck() = %v, want the hearts and two jokers", got)
+	}
+
+	_, err = s.CreateDeck(Deck{Remaining: 1, Cards: []Card{{Code: "AH"}}, Filter: Filter{Suits: []string{"H"}}})
+	if !errors.Is(err, ErrInvalidDeck) {
+		t.Errorf("CreateDeck() error = %v, want %v", err, ErrInvalidDeck)
+	}
+}
@@ -3,92 +3,140 @@ package creating
 import (
 	"errors"
 	"fmt"
-	"math/rand"
 	"strconv"
 	"strings"
+
+	"github.com/srgyrn/lucky-38/pkg/commitment"
 )
 
 // FrenchDeckCardTotal holds the total number of cards that a French playing card deck has
 const FrenchDeckCardTotal = 52
 
-var suits = map[byte]string{
-	'S': "SPADES",
-	'D': "DIAMONDS",
-	'H': "HEARTS",
-	'C': "CLUBS",
-}
+// MaxJokers is the number of jokers a deck can have, coded from X1 to X2
+const MaxJokers = 2
 
-var alphaValues = map[string]string{
-	"A": "ACE",
-	"K": "KING",
-	"Q": "QUEEN",
-	"J": "JACK",
-}
+// MaxDecks is the number of full decks a shoe can have
+const MaxDecks = 8
+
+// MaxPenetration is the percentage of a deck that can be dealt before its cut card
+const MaxPenetration = 99
+
+// Value and suit of a joker
+const (
+	JokerValue = "JOKER"
+	JokerSuit  = "NONE"
+)
 
 type (
 	Service interface {
 		CreateDeck(Deck) (Deck, error)
+		CreateDefinition(Definition) (DeckType, error)
 	}
 	Repository interface {
 		CreateDeck(*Deck) error
+		CreateDeckType(DeckType) error
+		FindDeckType(name string) (DeckType, error)
 	}
 
 	service struct {
-		r Repository
+		r        Repository
+		shuffler Shuffler
 	}
 
 	checkFn        func(Deck) error
 	InvalidCardErr struct {
 		Card Card
 	}
+
+	// DuplicateCardsErr lists codes of the cards that a partial deck has more times than its type does
+	DuplicateCardsErr struct {
+		Codes []string
+	}
 )
 
 var ErrInvalidDeck = errors.New("could not create deck")
 var ErrCreate = errors.New("insert failed")
+var ErrShuffle = errors.New("shuffle failed")
 var ErrInvalidCard *InvalidCardErr
+var ErrDuplicateCards *DuplicateCardsErr
 
 func (err *InvalidCardErr) Error() string {
 	return fmt.Sprintf("invalid card: %v", err.Card)
 }
 
+func (err *DuplicateCardsErr) Error() string {
+	return fmt.Sprintf("duplicate cards: %s", strings.Join(err.Codes, ","))
+}
+
+// NewService returns a Service that shuffles decks with CryptoShuffler
 func NewService(r Repository) Service {
-	return &service{r: r}
+	return NewServiceWithShuffler(r, CryptoShuffler{})
+}
+
+// NewServiceWithShuffler returns a Service that shuffles decks without a seed with the given Shuffler
+func NewServiceWithShuffler(r Repository, s Shuffler) Service {
+	return &service{r: r, shuffler: s}
 }
 
 // CreateDeck prepares cards in a deck, then communicates with repository to insert the deck and cards to DB.
 //
-// If any of the Deck.Cards have invalid value and/or suit (i.e. 50K or 10T), ErrInvalidCard is returned.
+// Deck.Type names a registered DeckType or a Definition stored in Repository, French deck by default. If the type is
+// unknown, ErrInvalidDeck is returned.
+// A deck without Deck.Cards is a full deck of its type in canonical order, or a partial deck of the cards Deck.Filter
+// keeps. Filtering a deck with Deck.Cards or by unknown suits or ranks returns ErrInvalidDeck.
+// If any of the Deck.Cards do not belong to the type (i.e. 50K or 10T), ErrInvalidCard is returned.
 // If Deck.Cards length and Deck.Remaining are not equal, ErrInvalidDeck is returned.
+// If any of the Deck.Cards is in the deck more times than in a full deck of its type, e.g. AS twice in a French deck,
+// ErrDuplicateCards is returned unless Deck.AllowDuplicates is set.
+// A full or filtered deck has Deck.Jokers jokers after the cards of its type. If there are more than MaxJokers jokers,
+// or jokers are asked for a deck of listed cards, ErrInvalidDeck is returned. Listed cards may include jokers by their
+// codes instead.
+// If Deck.Decks is more than 1, a shoe of that many full decks is created, every copy of a card has its own Card.Copy.
+// If there are more than MaxDecks decks, or decks are asked for a partial deck, ErrInvalidDeck is returned.
+// A shuffled deck with Deck.Reversals has every card turned upside down or not at random, Deck.Reversals is dropped if
+// the deck is not shuffled.
+// A deck with Deck.Penetration has a cut card placed after that percentage of its cards, Deck.CutCard is the number of
+// cards behind it. If Deck.Penetration is more than MaxPenetration, ErrInvalidDeck is returned.
+// A shuffled deck with Deck.Seed is always shuffled into the same order by PRNGShuffler, Deck.Seed is dropped if the
+// deck is not shuffled.
+// The deck is committed to its final order with a new salt, see package commitment.
+// In case shuffling fails, ErrShuffle is returned.
 // In case Repository returns an error, ErrCreate is returned.
 func (s *service) CreateDeck(d Deck) (Deck, error) {
-	checkCardSuit := func(deck Deck) error {
-		for _, c := range deck.Cards {
-			code := strings.TrimSpace(c.Code)
-			n := len(code)
-			if n < 2 || n > 3 {
-				return &InvalidCardErr{c}
-			}
+	typeName := d.Type
+	if "" == typeName {
+		typeName = FrenchDeck
+	}
 
-			if _, ok := suits[code[n-1]]; !ok {
-				return &InvalidCardErr{c}
+	deckType, ok := LookupDeckType(typeName)
+	if !ok {
+		var err error
+		if deckType, err = s.r.FindDeckType(typeName); err != nil {
+			if errors.Is(err, ErrDefinitionNotFound) {
+				return Deck{}, fmt.Errorf("%w: unknown type %q", ErrInvalidDeck, d.Type)
 			}
+
+			return Deck{}, fmt.Errorf("%w: %v", ErrCreate, err)
 		}
-		return nil
 	}
-	checkCardVal := func(deck Deck) error {
-		for _, c := range deck.Cards {
-			code := strings.TrimSpace(c.Code)
-			n := len(code)
-			if n < 2 || n > 3 {
-				return &InvalidCardErr{c}
-			}
 
-			val := code[:n-1]
-			if _, ok := alphaValues[val]; ok {
-				continue
-			}
+	if !d.Filter.empty() {
+		if 0 < len(d.Cards) {
+			return Deck{}, fmt.Errorf("%w: cards can not be both listed and filtered", ErrInvalidDeck)
+		}
 
-			if valInt, _ := strconv.Atoi(val); 2 > valInt || 11 < valInt {
+		cards, err := deckType.filter(d.Filter)
+		if err != nil {
+			return Deck{}, err
+		}
+		cards = append(cards, jokers(d.Jokers)...)
+		d.Cards, d.Remaining = cards, len(cards)
+	}
+
+	checkCardType := func(deck Deck) error {
+		for _, c := range deck.Cards {
+			code := strings.TrimSpace(c.Code)
+			if _, ok := deckType.find(code); !ok && !isJoker(code) {
 				return &InvalidCardErr{c}
 			}
 		}
@@ -102,36 +150,91 @@ func (s *service) CreateDeck(d Deck) (Deck, error) {
 
 		return nil
 	}
+	checkJokers := func(deck Deck) error {
+		if 0 > deck.Jokers || MaxJokers < deck.Jokers || (0 < deck.Jokers && 0 < len(deck.Cards) && deck.Filter.empty()) {
+			return ErrInvalidDeck
+		}
 
-	// Fill missing values of Card from code if deck is partial
-	for i := 0; i < len(d.Cards); i++ {
-		card := &d.Cards[i]
-		code := strings.TrimSpace(card.Code)
-		val := code[:len(code)-1]
-		suit := code[len(code)-1]
+		return nil
+	}
+	checkDecks := func(deck Deck) error {
+		if 0 > deck.Decks || MaxDecks < deck.Decks || (1 < deck.Decks && 0 < len(deck.Cards)) {
+			return ErrInvalidDeck
+		}
+
+		return nil
+	}
 
-		card.Value = val
-		if v, ok := alphaValues[val]; ok {
-			card.Value = v
+	checkPenetration := func(deck Deck) error {
+		if 0 > deck.Penetration || MaxPenetration < deck.Penetration {
+			return fmt.Errorf("%w: penetration must be between 0 and %d", ErrInvalidDeck, MaxPenetration)
 		}
 
-		card.Suit = suits[suit]
+		return nil
 	}
 
 	// validate cards
-	for _, fn := range []checkFn{checkCardAmount, checkCardVal, checkCardSuit} {
+	for _, fn := range []checkFn{checkCardAmount, checkJokers, checkDecks, checkPenetration, checkCardType} {
 		if err := fn(d); err != nil {
 			return Deck{}, err
 		}
 	}
 
-	// Generate cards in order if not partial
-	if FrenchDeckCardTotal == d.Remaining {
-		d.Cards = fullDeckGen(FrenchDeckCardTotal)
+	// Fill missing values of Card from code if deck is partial, codes are stored as the type has them
+	for i := 0; i < len(d.Cards); i++ {
+		card := &d.Cards[i]
+		code := strings.TrimSpace(card.Code)
+		if isJoker(code) {
+			card.Code, card.Value, card.Suit = strings.ToUpper(code), JokerValue, JokerSuit
+			continue
+		}
+
+		c, _ := deckType.find(code)
+		card.Code, card.Value, card.Suit = c.Code, c.Value, c.Suit
+	}
+
+	if !d.AllowDuplicates {
+		if err := checkDuplicates(d.Cards, deckType); err != nil {
+			return Deck{}, err
+		}
+	}
+
+	// Generate cards in order if not partial, Remaining is FrenchDeckCardTotal for full decks of any type until then
+	if FrenchDeckCardTotal == d.Remaining && 0 == len(d.Cards) {
+		d.Cards = append(deckType.Cards, jokers(d.Jokers)...)
+		if 1 < d.Decks {
+			d.Cards = shoe(d.Cards, d.Decks)
+		}
+		d.Remaining = len(d.Cards)
+	}
+
+	d.CutCard = 0
+	if 0 < d.Penetration {
+		d.CutCard = d.Remaining - d.Remaining*d.Penetration/100
 	}
 
 	if d.Shuffled {
-		d.shuffleCards()
+		shuffler := s.shuffler
+		if d.Seed != nil {
+			shuffler = NewPRNGShuffler(*d.Seed)
+		}
+
+		if err := d.shuffleCards(shuffler); err != nil {
+			return Deck{}, fmt.Errorf("%w: %v", ErrShuffle, err)
+		}
+
+		if d.Reversals {
+			if err := d.reverseCards(shuffler); err != nil {
+				return Deck{}, fmt.Errorf("%w: %v", ErrShuffle, err)
+			}
+		}
+	} else {
+		d.Seed = nil
+		d.Reversals = false
+	}
+
+	if err := d.commit(); err != nil {
+		return Deck{}, fmt.Errorf("%w: %v", ErrCreate, err)
 	}
 
 	err := s.r.CreateDeck(&d)
@@ -142,44 +245,139 @@ func (s *service) CreateDeck(d Deck) (Deck, error) {
 	return d, nil
 }
 
-// fullDeckGen generates all cards of a deck in order both value and suit
-func fullDeckGen(cardAmount int) []Card {
-	cards := make([]Card, cardAmount, cardAmount)
-	var index int
+// CreateDefinition stores a custom deck definition, decks of its type can be created by its name afterwards.
+// If the definition is not valid, ErrInvalidDefinition is returned. If there is a deck type with the same name,
+// ErrDefinitionExists is returned. In case Repository returns any other error, ErrCreate is returned.
+func (s *service) CreateDefinition(def Definition) (DeckType, error) {
+	t, err := def.DeckType()
+	if err != nil {
+		return DeckType{}, err
+	}
 
-	for _, s := range []byte{'S', 'D', 'C', 'H'} {
-		suitCode := string(s)
-		suit := suits[s]
+	if _, ok := LookupDeckType(t.Name); ok {
+		return DeckType{}, fmt.Errorf("%w: %s is a built-in type", ErrDefinitionExists, t.Name)
+	}
 
-		for j := 1; j <= 10; j++ {
-			val := strconv.Itoa(j)
-			if 1 == j {
-				val = "A"
-			}
-			cards[index] = Card{
-				Code:  val + suitCode,
-				Value: val,
-				Suit:  suit,
-			}
-			index++
+	if err := s.r.CreateDeckType(t); err != nil {
+		if errors.Is(err, ErrDefinitionExists) {
+			return DeckType{}, err
 		}
 
-		for _, v := range []string{"JACK", "QUEEN", "KING"} {
-			cards[index] = Card{
-				Code:  string(v[0]) + suitCode,
-				Value: v,
-				Suit:  suit,
+		return DeckType{}, fmt.Errorf("%w: %v", ErrCreate, err)
+	}
+
+	return t, nil
+}
+
+// shoe returns given number of copies of cards one after the other. Each card is numbered by its deck and its copy
+// within the deck, so every copy of a card in the shoe has its own Copy, from 1.
+func shoe(cards []Card, decks int) []Card {
+	perDeck := 1
+	for _, c := range cards {
+		if perDeck < c.Copy {
+			perDeck = c.Copy
+		}
+	}
+
+	result := make([]Card, 0, decks*len(cards))
+	for i := 1; i <= decks; i++ {
+		for _, c := range cards {
+			n := c.Copy
+			if 0 == n {
+				n = 1
 			}
-			index++
+			c.Copy = (i-1)*perDeck + n
+			result = append(result, c)
 		}
 	}
 
+	return result
+}
+
+// checkDuplicates returns DuplicateCardsErr if any of the cards is more times in cards than in t, a joker can be once
+func checkDuplicates(cards []Card, t DeckType) error {
+	allowed := make(map[string]int)
+	for _, c := range t.Cards {
+		allowed[c.Code]++
+	}
+
+	var duplicates []string
+	seen := make(map[string]int)
+	for _, c := range cards {
+		seen[c.Code]++
+		limit := allowed[c.Code]
+		if isJoker(c.Code) {
+			limit = 1
+		}
+
+		// every code is listed once, when it is first over the limit
+		if seen[c.Code] == limit+1 {
+			duplicates = append(duplicates, c.Code)
+		}
+	}
+
+	if 0 < len(duplicates) {
+		return &DuplicateCardsErr{Codes: duplicates}
+	}
+
+	return nil
+}
+
+// jokers returns n jokers coded from X1 to Xn
+func jokers(n int) []Card {
+	var cards []Card
+	for i := 1; i <= n; i++ {
+		cards = append(cards, Card{Code: "X" + strconv.Itoa(i), Value: JokerValue, Suit: JokerSuit})
+	}
+
 	return cards
 }
 
-func (d *Deck) shuffleCards() {
-	for i := 0; i < len(d.Cards); i++ {
-		j := i + (rand.Intn(len(d.Cards)) % (len(d.Cards) - i))
-		d.Cards[i], d.Cards[j] = d.Cards[j], d.Cards[i]
+// isJoker reports if code is one of X1 to X<MaxJokers>, in any case
+func isJoker(code string) bool {
+	if 2 != len(code) || ('X' != code[0] && 'x' != code[0]) {
+		return false
+	}
+
+	n, err := strconv.Atoi(code[1:])
+	return err == nil && 1 <= n && MaxJokers >= n
+}
+
+// commit sets a new salt and the commitment to the current card order and orientation
+func (d *Deck) commit() error {
+	salt, err := commitment.NewSalt()
+	if err != nil {
+		return err
+	}
+
+	codes := make([]string, len(d.Cards))
+	for i, c := range d.Cards {
+		codes[i] = commitment.Entry(c.Code, c.Reversed)
+	}
+
+	d.Salt = salt
+	d.Commitment = commitment.Compute(salt, codes)
+
+	return nil
+}
+
+// reverseCards turns every card upside down or not, each orientation is equally likely as long as s is unbiased
+func (d *Deck) reverseCards(s Shuffler) error {
+	for i := range d.Cards {
+		sides := []bool{false, true}
+		if err := s.Shuffle(len(sides), func(i, j int) { sides[i], sides[j] = sides[j], sides[i] }); err != nil {
+			return err
+		}
+
+		d.Cards[i].Reversed = sides[0]
 	}
+
+	return nil
+}
+
+// shuffleCards puts cards of the deck in the order given by s
+func (d *Deck) shuffleCards(s Shuffler) error {
+	return s.Shuffle(len(d.Cards), func(i, j int) {
+		d.Cards[i], d.Cards[j] = d.Cards[j], d.Cards[i]
+	})
 }
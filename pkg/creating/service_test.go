@@ -4,6 +4,8 @@ import (
 	"errors"
 	"reflect"
 	"testing"
+
+	"github.com/srgyrn/lucky-38/pkg/commitment"
 )
 
 func Test_service_CreateDeck(t *testing.T) {
@@ -47,6 +49,55 @@ func Test_service_CreateDeck(t *testing.T) {
 			wantErr:     true,
 			errWantType: ErrInvalidCard,
 		},
+		{
+			name:   "partial/lenient codes",
+			fields: fields{r: &mockDB{}},
+			deck:   Deck{Remaining: 3, Cards: []Card{{Code: "th"}, {Code: "q♣"}, {Code: "x1"}}},
+			want: Deck{
+				Remaining: 3,
+				Cards: []Card{
+					{Code: "10H", Value: "10", Suit: "HEARTS"},
+					{Code: "QC", Value: "QUEEN", Suit: "CLUBS"},
+					{Code: "X1", Value: JokerValue, Suit: JokerSuit},
+				},
+			},
+		},
+		{
+			name:        "partial/duplicates",
+			fields:      fields{r: &mockDB{}},
+			deck:        Deck{Remaining: 6, Cards: []Card{{Code: "AS"}, {Code: "as"}, {Code: "KD"}, {Code: "X1"}, {Code: "X1"}, {Code: "AS"}}},
+			want:        Deck{},
+			wantErr:     true,
+			errWantType: ErrDuplicateCards,
+		},
+		{
+			name:   "partial/duplicates allowed",
+			fields: fields{r: &mockDB{}},
+			deck:   Deck{Remaining: 2, AllowDuplicates: true, Cards: []Card{{Code: "AS"}, {Code: "AS"}}},
+			want: Deck{
+				Remaining:       2,
+				AllowDuplicates: true,
+				Cards:           []Card{{Code: "AS", Value: "ACE", Suit: "SPADES"}, {Code: "AS", Value: "ACE", Suit: "SPADES"}},
+			},
+		},
+		{
+			name:   "partial/as many as the type has",
+			fields: fields{r: &mockDB{}},
+			deck:   Deck{Type: PinochleDeck, Remaining: 2, Cards: []Card{{Code: "9H"}, {Code: "9H"}}},
+			want: Deck{
+				Type:      PinochleDeck,
+				Remaining: 2,
+				Cards:     []Card{{Code: "9H", Value: "9", Suit: "HEARTS"}, {Code: "9H", Value: "9", Suit: "HEARTS"}},
+			},
+		},
+		{
+			name:        "no such card",
+			fields:      fields{r: &mockDB{}},
+			deck:        Deck{Remaining: 2, Cards: []Card{{Code: "AH"}, {Code: "11H"}}},
+			want:        Deck{},
+			wantErr:     true,
+			errWantType: ErrInvalidCard,
+		},
 		{
 			name: "partial/cards missing",
 			fields: fields{
@@ -126,6 +177,172 @@ func Test_service_CreateDeck(t *testing.T) {
 			wantErr:     false,
 			errWantType: ErrCreate,
 		},
+		{
+			name:   "full/jokers",
+			fields: fields{r: &mockDB{}},
+			deck:   Deck{Remaining: 52, Jokers: 2},
+			want: Deck{
+				Remaining: 54,
+				Jokers:    2,
+				Cards: append(append([]Card(nil), fullDeck...),
+					Card{Code: "X1", Value: JokerValue, Suit: JokerSuit},
+					Card{Code: "X2", Value: JokerValue, Suit: JokerSuit},
+				),
+			},
+		},
+		{
+			name:        "full/too many jokers",
+			fields:      fields{r: &mockDB{}},
+			deck:        Deck{Remaining: 52, Jokers: 3},
+			want:        Deck{},
+			wantErr:     true,
+			errWantType: ErrInvalidDeck,
+		},
+		{
+			name:   "partial/jokers",
+			fields: fields{r: &mockDB{}},
+			deck: Deck{
+				Remaining: 2,
+				Cards:     []Card{{Code: "AS"}, {Code: "X2"}},
+			},
+			want: Deck{
+				Remaining: 2,
+				Cards: []Card{
+					{Code: "AS", Value: "ACE", Suit: "SPADES"},
+					{Code: "X2", Value: JokerValue, Suit: JokerSuit},
+				},
+			},
+		},
+		{
+			name:   "partial/unknown joker",
+			fields: fields{r: &mockDB{}},
+			deck: Deck{
+				Remaining: 2,
+				Cards:     []Card{{Code: "AS"}, {Code: "X3"}},
+			},
+			want:        Deck{},
+			wantErr:     true,
+			errWantType: ErrInvalidCard,
+		},
+		{
+			name:   "partial/jokers count",
+			fields: fields{r: &mockDB{}},
+			deck: Deck{
+				Remaining: 1,
+				Jokers:    1,
+				Cards:     []Card{{Code: "AS"}},
+			},
+			want:        Deck{},
+			wantErr:     true,
+			errWantType: ErrInvalidDeck,
+		},
+		{
+			name:   "shoe",
+			fields: fields{r: &mockDB{}},
+			deck:   Deck{Remaining: 52, Decks: 2, Jokers: 1},
+			want: Deck{
+				Remaining: 106,
+				Decks:     2,
+				Jokers:    1,
+				Cards: append(
+					shoeCopy(append(append([]Card(nil), fullDeck...), Card{Code: "X1", Value: JokerValue, Suit: JokerSuit}), 1),
+					shoeCopy(append(append([]Card(nil), fullDeck...), Card{Code: "X1", Value: JokerValue, Suit: JokerSuit}), 2)...,
+				),
+			},
+		},
+		{
+			name:        "shoe/too many decks",
+			fields:      fields{r: &mockDB{}},
+			deck:        Deck{Remaining: 52, Decks: MaxDecks + 1},
+			want:        Deck{},
+			wantErr:     true,
+			errWantType: ErrInvalidDeck,
+		},
+		{
+			name:   "shoe/partial",
+			fields: fields{r: &mockDB{}},
+			deck: Deck{
+				Remaining: 1,
+				Decks:     2,
+				Cards:     []Card{{Code: "AS"}},
+			},
+			want:        Deck{},
+			wantErr:     true,
+			errWantType: ErrInvalidDeck,
+		},
+		{
+			name:   "type/full",
+			fields: fields{r: &mockDB{}},
+			deck:   Deck{Type: EuchreDeck, Remaining: 52, Jokers: 1},
+			want: Deck{
+				Type:      EuchreDeck,
+				Remaining: 25,
+				Jokers:    1,
+				Cards:     append(euchreDeck(), Card{Code: "X1", Value: JokerValue, Suit: JokerSuit}),
+			},
+		},
+		{
+			name:   "type/partial",
+			fields: fields{r: &mockDB{}},
+			deck: Deck{
+				Type:      Spanish40Deck,
+				Remaining: 3,
+				Cards:     []Card{{Code: "1O"}, {Code: "11E"}, {Code: "7B"}},
+			},
+			want: Deck{
+				Type:      Spanish40Deck,
+				Remaining: 3,
+				Cards: []Card{
+					{Code: "1O", Value: "ACE", Suit: "COINS"},
+					{Code: "11E", Value: "KNIGHT", Suit: "SWORDS"},
+					{Code: "7B", Value: "7", Suit: "CLUBS"},
+				},
+			},
+		},
+		{
+			name:   "type/card not in type",
+			fields: fields{r: &mockDB{}},
+			deck: Deck{
+				Type:      PiquetDeck,
+				Remaining: 2,
+				Cards:     []Card{{Code: "AS"}, {Code: "2S"}},
+			},
+			want:        Deck{},
+			wantErr:     true,
+			errWantType: ErrInvalidCard,
+		},
+		{
+			name:   "penetration",
+			fields: fields{r: &mockDB{}},
+			deck:   Deck{Remaining: 4, Penetration: 75, CutCard: 4, Cards: []Card{{Code: "AS"}, {Code: "2S"}, {Code: "3S"}, {Code: "4S"}}},
+			want: Deck{
+				Remaining:   4,
+				Penetration: 75,
+				CutCard:     1,
+				Cards: []Card{
+					{Code: "AS", Value: "ACE", Suit: "SPADES"},
+					{Code: "2S", Value: "2", Suit: "SPADES"},
+					{Code: "3S", Value: "3", Suit: "SPADES"},
+					{Code: "4S", Value: "4", Suit: "SPADES"},
+				},
+			},
+		},
+		{
+			name:        "penetration/too deep",
+			fields:      fields{r: &mockDB{}},
+			deck:        Deck{Remaining: 52, Penetration: 100},
+			want:        Deck{},
+			wantErr:     true,
+			errWantType: ErrInvalidDeck,
+		},
+		{
+			name:        "type/unknown",
+			fields:      fields{r: &mockDB{}},
+			deck:        Deck{Type: "hanafuda", Remaining: 52},
+			want:        Deck{},
+			wantErr:     true,
+			errWantType: ErrInvalidDeck,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -138,11 +355,23 @@ func Test_service_CreateDeck(t *testing.T) {
 				return
 			}
 
-			if tt.wantErr && !errors.As(err, &tt.errWantType) {
+			if tt.wantErr && !errors.Is(err, tt.errWantType) && reflect.TypeOf(err) != reflect.TypeOf(tt.errWantType) {
 				t.Errorf("CreateDeck() error want %T, got %T", tt.errWantType, err)
 				return
 			}
 
+			if !tt.wantErr {
+				var codes []string
+				for _, c := range got.Cards {
+					codes = append(codes, commitment.Entry(c.Code, c.Reversed))
+				}
+
+				if !commitment.Verify(got.Commitment, got.Salt, codes) {
+					t.Errorf("CreateDeck() commitment %s does not match the cards", got.Commitment)
+				}
+				got.Commitment, got.Salt = "", ""
+			}
+
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("CreateDeck() got = %v, want %v", got, tt.want)
 			}
@@ -187,7 +416,7 @@ func Test_service_CreateDeck_shuffled(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := &service{r: &mockDB{}}
+			s := &service{r: &mockDB{}, shuffler: CryptoShuffler{}}
 			got, err := s.CreateDeck(tt.deck)
 			if err != nil {
 				t.Errorf("CreateDeck() error = %v", err)
@@ -201,16 +430,166 @@ func Test_service_CreateDeck_shuffled(t *testing.T) {
 	}
 }
 
+func Test_service_CreateDeck_seeded(t *testing.T) {
+	seed := int64(38)
+	s := &service{r: &mockDB{}}
+
+	first, err := s.CreateDeck(Deck{Shuffled: true, Remaining: 52, Seed: &seed})
+	if err != nil {
+		t.Fatalf("CreateDeck() error = %v", err)
+	}
+
+	second, err := s.CreateDeck(Deck{Shuffled: true, Remaining: 52, Seed: &seed})
+	if err != nil {
+		t.Fatalf("CreateDeck() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(first.Cards, second.Cards) {
+		t.Errorf("CreateDeck() cards differ for the same seed: %v, %v", first.Cards, second.Cards)
+	}
+
+	if reflect.DeepEqual(first.Cards, fullDeck) {
+		t.Errorf("CreateDeck() got = %v, want cards shuffled", first)
+	}
+
+	if first.Seed == nil || seed != *first.Seed {
+		t.Errorf("CreateDeck() seed = %v, want %d", first.Seed, seed)
+	}
+
+	ordered, err := s.CreateDeck(Deck{Shuffled: false, Remaining: 52, Seed: &seed})
+	if err != nil {
+		t.Fatalf("CreateDeck() error = %v", err)
+	}
+
+	if ordered.Seed != nil {
+		t.Errorf("CreateDeck() seed = %d, want no seed for a deck in order", *ordered.Seed)
+	}
+}
+
+func Test_service_CreateDeck_reversedCommitment(t *testing.T) {
+	seed := int64(16)
+	got, err := NewService(&mockDB{}).CreateDeck(Deck{Type: TarotDeck, Shuffled: true, Reversals: true, Seed: &seed, Remaining: 52})
+	if err != nil {
+		t.Fatalf("CreateDeck() error = %v", err)
+	}
+
+	var codes []string
+	reversed := -1
+	for i, c := range got.Cards {
+		codes = append(codes, commitment.Entry(c.Code, c.Reversed))
+		if c.Reversed && 0 > reversed {
+			reversed = i
+		}
+	}
+
+	if 0 > reversed {
+		t.Fatalf("CreateDeck() got no reversed card")
+	}
+
+	if !commitment.Verify(got.Commitment, got.Salt, codes) {
+		t.Errorf("CreateDeck() commitment %s does not match the cards and their orientation", got.Commitment)
+	}
+
+	codes[reversed] = got.Cards[reversed].Code
+	if commitment.Verify(got.Commitment, got.Salt, codes) {
+		t.Errorf("CreateDeck() commitment %s matches %s turned upright", got.Commitment, codes[reversed])
+	}
+}
+
+// shoeCopy sets copy of every card
+func shoeCopy(cards []Card, n int) []Card {
+	for i := range cards {
+		cards[i].Copy = n
+	}
+
+	return cards
+}
+
+func Test_service_CreateDeck_pinochleShoe(t *testing.T) {
+	s := NewService(&mockDB{})
+	got, err := s.CreateDeck(Deck{Type: PinochleDeck, Remaining: FrenchDeckCardTotal, Decks: 2})
+	if err != nil {
+		t.Fatalf("CreateDeck() error = %v", err)
+	}
+
+	copies := make(map[string][]int)
+	for _, c := range got.Cards {
+		copies[c.Code] = append(copies[c.Code], c.Copy)
+	}
+
+	if 96 != len(got.Cards) || 24 != len(copies) {
+		t.Fatalf("CreateDeck() got %d cards of %d codes, want 96 of 24", len(got.Cards), len(copies))
+	}
+
+	for code, got := range copies {
+		if want := []int{1, 2, 3, 4}; !reflect.DeepEqual(got, want) {
+			t.Errorf("CreateDeck() %s copies = %v, want %v", code, got, want)
+		}
+	}
+}
+
+func Test_service_CreateDeck_duplicates(t *testing.T) {
+	s := NewService(&mockDB{})
+	_, err := s.CreateDeck(Deck{Remaining: 6, Cards: []Card{{Code: "AS"}, {Code: "as"}, {Code: "KD"}, {Code: "X1"}, {Code: "X1"}, {Code: "AS"}}})
+
+	var duplicates *DuplicateCardsErr
+	if !errors.As(err, &duplicates) {
+		t.Fatalf("CreateDeck() error = %v, want %T", err, duplicates)
+	}
+
+	if want := []string{"AS", "X1"}; !reflect.DeepEqual(duplicates.Codes, want) {
+		t.Errorf("CreateDeck() duplicates = %v, want %v", duplicates.Codes, want)
+	}
+}
+
+func euchreDeck() []Card {
+	var cards []Card
+	for _, s := range []struct{ code, name string }{{"S", "SPADES"}, {"D", "DIAMONDS"}, {"C", "CLUBS"}, {"H", "HEARTS"}} {
+		for _, v := range [][2]string{{"A", "ACE"}, {"9", "9"}, {"10", "10"}, {"J", "JACK"}, {"Q", "QUEEN"}, {"K", "KING"}} {
+			cards = append(cards, Card{Code: v[0] + s.code, Value: v[1], Suit: s.name})
+		}
+	}
+
+	return cards
+}
+
 type mockDB struct {
-	err error
+	err   error
+	types map[string]DeckType
 }
 
 func (mdb *mockDB) CreateDeck(deck *Deck) error {
 	return mdb.err
 }
 
+func (mdb *mockDB) CreateDeckType(t DeckType) error {
+	if mdb.err != nil {
+		return mdb.err
+	}
+
+	if _, ok := mdb.types[t.Name]; ok {
+		return ErrDefinitionExists
+	}
+
+	if nil == mdb.types {
+		mdb.types = make(map[string]DeckType)
+	}
+	mdb.types[t.Name] = t
+
+	return nil
+}
+
+func (mdb *mockDB) FindDeckType(name string) (DeckType, error) {
+	t, ok := mdb.types[name]
+	if !ok {
+		return DeckType{}, ErrDefinitionNotFound
+	}
+
+	return t, nil
+}
+
 var fullDeck []Card = []Card{
-	{Code: "AS", Value: "A", Suit: "SPADES"},
+	{Code: "AS", Value: "ACE", Suit: "SPADES"},
 	{Code: "2S", Value: "2", Suit: "SPADES"},
 	{Code: "3S", Value: "3", Suit: "SPADES"},
 	{Code: "4S", Value: "4", Suit: "SPADES"},
@@ -223,7 +602,7 @@ var fullDeck []Card = []Card{
 	{Code: "JS", Value: "JACK", Suit: "SPADES"},
 	{Code: "QS", Value: "QUEEN", Suit: "SPADES"},
 	{Code: "KS", Value: "KING", Suit: "SPADES"},
-	{Code: "AD", Value: "A", Suit: "DIAMONDS"},
+	{Code: "AD", Value: "ACE", Suit: "DIAMONDS"},
 	{Code: "2D", Value: "2", Suit: "DIAMONDS"},
 	{Code: "3D", Value: "3", Suit: "DIAMONDS"},
 	{Code: "4D", Value: "4", Suit: "DIAMONDS"},
@@ -236,7 +615,7 @@ var fullDeck []Card = []Card{
 	{Code: "JD", Value: "JACK", Suit: "DIAMONDS"},
 	{Code: "QD", Value: "QUEEN", Suit: "DIAMONDS"},
 	{Code: "KD", Value: "KING", Suit: "DIAMONDS"},
-	{Code: "AC", Value: "A", Suit: "CLUBS"},
+	{Code: "AC", Value: "ACE", Suit: "CLUBS"},
 	{Code: "2C", Value: "2", Suit: "CLUBS"},
 	{Code: "3C", Value: "3", Suit: "CLUBS"},
 	{Code: "4C", Value: "4", Suit: "CLUBS"},
@@ -249,7 +628,7 @@ var fullDeck []Card = []Card{
 	{Code: "JC", Value: "JACK", Suit: "CLUBS"},
 	{Code: "QC", Value: "QUEEN", Suit: "CLUBS"},
 	{Code: "KC", Value: "KING", Suit: "CLUBS"},
-	{Code: "AH", Value: "A", Suit: "HEARTS"},
+	{Code: "AH", Value: "ACE", Suit: "HEARTS"},
 	{Code: "2H", Value: "2", Suit: "HEARTS"},
 	{Code: "3H", Value: "3", Suit: "HEARTS"},
 	{Code: "4H", Value: "4", Suit: "HEARTS"},
@@ -0,0 +1,48 @@
+package creating
+
+import (
+	cryptorand "crypto/rand"
+	"fmt"
+	"math/big"
+	"math/rand"
+)
+
+type (
+	// Shuffler puts n elements in a random order by calling swap, like rand.Shuffle.
+	Shuffler interface {
+		Shuffle(n int, swap func(i, j int)) error
+	}
+
+	// CryptoShuffler runs Fisher–Yates shuffle with indexes drawn from crypto/rand. It is the default for real tables.
+	CryptoShuffler struct{}
+
+	// PRNGShuffler runs Fisher–Yates shuffle with a seeded math/rand source, so the same seed gives the same order.
+	// It is not fit for real tables, use it for reproducible decks and tests.
+	PRNGShuffler struct {
+		rnd *rand.Rand
+	}
+)
+
+// Shuffle swaps every element, starting from the last one, with an element at or before it chosen uniformly.
+func (CryptoShuffler) Shuffle(n int, swap func(i, j int)) error {
+	for i := n - 1; i > 0; i-- {
+		j, err := cryptorand.Int(cryptorand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return fmt.Errorf("error at reading random index: %v", err)
+		}
+
+		swap(i, int(j.Int64()))
+	}
+
+	return nil
+}
+
+func NewPRNGShuffler(seed int64) *PRNGShuffler {
+	return &PRNGShuffler{rnd: rand.New(rand.NewSource(seed))}
+}
+
+// Shuffle uses rand.Rand.Shuffle which is an unbiased Fisher–Yates shuffle.
+func (s *PRNGShuffler) Shuffle(n int, swap func(i, j int)) error {
+	s.rnd.Shuffle(n, swap)
+	return nil
+}
@@ -0,0 +1,129 @@
+package creating
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestShuffler_Shuffle(t *testing.T) {
+	tests := []struct {
+		name     string
+		shuffler Shuffler
+	}{
+		{name: "crypto", shuffler: CryptoShuffler{}},
+		{name: "prng", shuffler: NewPRNGShuffler(38)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ordered := make([]int, FrenchDeckCardTotal)
+			for i := range ordered {
+				ordered[i] = i
+			}
+
+			got := append([]int(nil), ordered...)
+			err := tt.shuffler.Shuffle(len(got), func(i, j int) {
+				got[i], got[j] = got[j], got[i]
+			})
+			if err != nil {
+				t.Fatalf("Shuffle() error = %v", err)
+			}
+
+			if reflect.DeepEqual(got, ordered) {
+				t.Errorf("Shuffle() = %v, want elements shuffled", got)
+			}
+
+			seen := make(map[int]bool, len(got))
+			for _, v := range got {
+				seen[v] = true
+			}
+
+			if len(seen) != len(ordered) {
+				t.Errorf("Shuffle() = %v, want a permutation of %v", got, ordered)
+			}
+		})
+	}
+}
+
+func TestPRNGShuffler_Shuffle_seeded(t *testing.T) {
+	shuffle := func(seed int64) []int {
+		got := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+		NewPRNGShuffler(seed).Shuffle(len(got), func(i, j int) {
+			got[i], got[j] = got[j], got[i]
+		})
+
+		return got
+	}
+
+	if first, second := shuffle(38), shuffle(38); !reflect.DeepEqual(first, second) {
+		t.Errorf("Shuffle() = %v and %v, want the same order for the same seed", first, second)
+	}
+
+	if first, second := shuffle(38), shuffle(39); reflect.DeepEqual(first, second) {
+		t.Errorf("Shuffle() = %v for different seeds, want different orders", first)
+	}
+}
+
+func Test_service_CreateDeck_shuffleFails(t *testing.T) {
+	s := NewServiceWithShuffler(&mockDB{}, &mockShuffler{err: errors.New("no entropy")})
+
+	_, err := s.CreateDeck(Deck{Shuffled: true, Remaining: FrenchDeckCardTotal})
+	if !errors.Is(err, ErrShuffle) {
+		t.Errorf("CreateDeck() error = %v, want %v", err, ErrShuffle)
+	}
+}
+
+func Test_service_CreateDeck_reversals(t *testing.T) {
+	s := NewService(&mockDB{})
+	seed := int64(38)
+	orientations := func(d Deck) []bool {
+		var reversed []bool
+		for _, c := range d.Cards {
+			reversed = append(reversed, c.Reversed)
+		}
+
+		return reversed
+	}
+
+	first, err := s.CreateDeck(Deck{Type: TarotDeck, Shuffled: true, Reversals: true, Seed: &seed, Remaining: FrenchDeckCardTotal})
+	if err != nil {
+		t.Fatalf("CreateDeck() error = %v", err)
+	}
+
+	second, _ := s.CreateDeck(Deck{Type: TarotDeck, Shuffled: true, Reversals: true, Seed: &seed, Remaining: FrenchDeckCardTotal})
+	if !reflect.DeepEqual(orientations(first), orientations(second)) {
+		t.Errorf("CreateDeck() reversed %v and %v, want the same orientations for the same seed", orientations(first), orientations(second))
+	}
+
+	var reversed int
+	for _, r := range orientations(first) {
+		if r {
+			reversed++
+		}
+	}
+
+	// 78 fair coin flips land within 20 and 58 almost surely
+	if 20 > reversed || 58 < reversed {
+		t.Errorf("CreateDeck() reversed %d of %d cards, want about half", reversed, len(first.Cards))
+	}
+
+	upright, _ := s.CreateDeck(Deck{Type: TarotDeck, Reversals: true, Remaining: FrenchDeckCardTotal})
+	if upright.Reversals {
+		t.Errorf("CreateDeck() = %v, want reversals dropped for a deck in order", upright)
+	}
+
+	for _, c := range upright.Cards {
+		if c.Reversed {
+			t.Errorf("CreateDeck() card %v is reversed in a deck in order", c)
+		}
+	}
+}
+
+type mockShuffler struct {
+	err error
+}
+
+func (ms *mockShuffler) Shuffle(n int, swap func(i, j int)) error {
+	return ms.err
+}
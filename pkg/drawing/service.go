@@ -2,60 +2,633 @@ package drawing
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
+
+	"github.com/srgyrn/lucky-38/pkg/card"
+	"github.com/srgyrn/lucky-38/pkg/creating"
 )
 
 type (
+	// Card is a card of a deck. Copy tells which deck of a shoe the card comes from, it is omitted for single decks.
+	// Reversed tells if the card is upside down in a deck created with reversals. Pile names the pile a drawn card is
+	// in, it is omitted for cards that are not in a pile.
+	// Rank, from 1 for ace to 13 for king, and Color are set for French playing cards only.
 	Card struct {
-		ID    int    `json:"-"`
-		Value string `json:"value"`
-		Suit  string `json:"suit"`
-		Code  string `json:"code"`
+		ID       int    `json:"-"`
+		Value    string `json:"value"`
+		Suit     string `json:"suit"`
+		Code     string `json:"code"`
+		Rank     int    `json:"rank,omitempty"`
+		Color    string `json:"color,omitempty"`
+		Copy     int    `json:"copy,omitempty"`
+		Reversed bool   `json:"reversed,omitempty"`
+		Pile     string `json:"pile,omitempty"`
+	}
+
+	// Mode tells which of the available cards of a deck are drawn
+	Mode string
+
+	// Options tell how cards are drawn. Mode is ModeTop if empty. Codes are the cards to draw in ModeSpecific, in the
+	// given order. Pile, if not empty, is the pile drawn cards are put in.
+	Options struct {
+		Mode  Mode
+		Codes []string
+		Pile  string
+	}
+
+	// Hand is the cards dealt to a seat, in the order they are dealt
+	Hand struct {
+		Seat  string `json:"seat"`
+		Cards []Card `json:"cards"`
 	}
 
+	// Pick chooses cards among the given cards of a deck.
+	Pick func(cards []Card) ([]Card, error)
+
+	// Split deals available cards of a deck, top of the deck first, into hands.
+	Split func(available []Card) ([]Hand, error)
+
+	// Arrange returns available cards of a deck, top of the deck first, in the order they should be dealt from now on.
+	Arrange func(available []Card) ([]Card, error)
+
+	// Rotate returns available cards of a deck, top of the deck first, in the order they are in after a cut, and the
+	// position cut at, i.e. the number of cards moved from the top to the bottom.
+	Rotate func(available []Card) ([]Card, int, error)
+
 	Repository interface {
-		FindAvailableCardByDeckID(uuid.UUID) ([]Card, error)
-		DrawCards(uuid.UUID, ...Card) error
+		// DrawCards marks the cards chosen by Pick among available cards, top of the deck first, as drawn,
+		// puts them on top of the given pile unless pile is empty, records the draw with its requester and returns
+		// the cards.
+		// Implementations must run it atomically per deck: no other draw on the same deck may see the available
+		// cards until the picked ones are marked.
+		DrawCards(deckID uuid.UUID, pile, requester string, pick Pick) ([]Card, error)
+		// ReturnCards puts the cards chosen by Pick among drawn cards, with their piles, back to the deck, records
+		// the return from pile with its requester, then reorders available cards as Arrange says and returns the
+		// returned cards. Pile is recorded only, returned cards may come from any pile. If shuffled is set, the
+		// reorder is recorded as a shuffle after the return, even if no card is returned.
+		// Implementations must run it atomically per deck, just like DrawCards.
+		ReturnCards(deckID uuid.UUID, pile, requester string, shuffled bool, pick Pick, arrange Arrange) ([]Card, error)
+		// MoveCards puts the cards chosen by Pick among drawn cards, with their piles, on top of the given pile in
+		// the picked order, records the move with its requester and returns the moved cards.
+		// Implementations must run it atomically per deck, just like DrawCards.
+		MoveCards(deckID uuid.UUID, pile, requester string, pick Pick) ([]Card, error)
+		// DealCards marks the cards of the hands Split makes of available cards, top of the deck first, as drawn,
+		// puts each hand on top of the pile named after its seat, records every hand as a deal with its requester
+		// and returns the hands. Implementations must run it atomically per deck, just like DrawCards.
+		DealCards(deckID uuid.UUID, requester string, split Split) ([]Hand, error)
+		// BurnCards draws the cards chosen by Pick like DrawCards does into BurnPile, recording the draw as a burn.
+		BurnCards(deckID uuid.UUID, requester string, pick Pick) ([]Card, error)
+		// PeekCards passes available cards, top of the deck first, to Pick, records the picked cards as a peek with
+		// its requester and returns them, leaving the deck as it is.
+		PeekCards(deckID uuid.UUID, requester string, pick Pick) ([]Card, error)
+		// CutCards reorders available cards, top of the deck first, as Rotate says and records a cut at the position
+		// Rotate returns with its requester. Implementations must run it atomically per deck, just like DrawCards.
+		CutCards(deckID uuid.UUID, requester string, rotate Rotate) error
+		// FindCutCard returns the number of available cards left when the cut card of the deck is reached, 0 if the
+		// deck has no cut card.
+		FindCutCard(deckID uuid.UUID) (int, error)
 	}
 
 	Service interface {
-		Draw(deckID string, n int) ([]Card, error)
+		Draw(deckID string, n int, requester string) ([]Card, bool, error)
+		DrawInto(deckID, pile string, n int, requester string) ([]Card, bool, error)
+		DrawWith(deckID string, n int, opts Options, requester string) ([]Card, bool, error)
+		Return(deckID string, codes []string, shuffle bool, requester string) ([]Card, error)
+		ReturnPile(deckID, pile string, shuffle bool, requester string) ([]Card, error)
+		Move(deckID, from, to string, codes []string, requester string) ([]Card, error)
+		Deal(deckID string, seats []string, n int, requester string) ([]Hand, bool, error)
+		Burn(deckID string, n int, requester string) (int, bool, error)
+		Peek(deckID string, n int, requester string) ([]Card, error)
+		Cut(deckID string, position int, requester string) (int, error)
 	}
 
 	service struct {
-		r Repository
+		r        Repository
+		shuffler creating.Shuffler
 	}
 )
 
+// Modes of drawing cards
+const (
+	// ModeTop draws cards from the top of the deck
+	ModeTop Mode = "top"
+	// ModeBottom draws cards from the bottom of the deck, the bottom card first
+	ModeBottom Mode = "bottom"
+	// ModeRandom draws cards from positions of the deck chosen uniformly
+	ModeRandom Mode = "random"
+	// ModeSpecific draws the cards with given codes
+	ModeSpecific Mode = "specific"
+)
+
 var ErrNotFound = errors.New("deck or remaining cards not found")
 var ErrInsufficientRemainingCard = errors.New("remaining cards are less than the requested amount to draw")
+var ErrInvalidAmount = errors.New("amount to draw must be at least 1")
+var ErrCardNotDrawn = errors.New("card is not drawn from the deck")
+var ErrDeckClosed = errors.New("deck is closed")
+var ErrInvalidPile = errors.New("invalid pile")
+var ErrCardNotInPile = errors.New("card is not in the pile")
+var ErrInvalidMode = errors.New("invalid draw mode")
+var ErrCardNotAvailable = errors.New("card is not available in the deck")
+var ErrInvalidCut = errors.New("deck can not be cut at the position")
+
+// BurnPile is the pile burned cards are put in
+const BurnPile = "burn"
+
+// Limits of piles and of seats to deal to
+const (
+	MaxPileNameLength = 40
+	MaxSeats          = 100
+)
+
+// Seats returns names of n seats, player1 to playerN
+func Seats(n int) []string {
+	seats := make([]string, 0, n)
+	for i := 1; i <= n; i++ {
+		seats = append(seats, fmt.Sprintf("player%d", i))
+	}
 
+	return seats
+}
+
+// NewService returns a Service that shuffles returned cards and chooses cards drawn at random with
+// creating.CryptoShuffler
 func NewService(r Repository) Service {
-	return &service{r: r}
+	return &service{r: r, shuffler: creating.CryptoShuffler{}}
 }
 
-// Draw marks n amount of cards as "drawn" from the deck with given deckID and returns them.
+// Draw marks n amount of cards as "drawn" from the deck with given deckID and returns them, reporting if the cut card
+// of the deck is reached, so the deck is due to be shuffled. Every draw after the cut card reports it.
+// The draw is recorded in the deck's history on behalf of requester.
 // If n is less than the number of available cards, ErrInsufficientRemainingCard is returned.
-func (s *service) Draw(deckID string, n int) ([]Card, error) {
+// Concurrent draws on the same deck never return the same card. If the deck is closed, ErrDeckClosed is returned.
+func (s *service) Draw(deckID string, n int, requester string) ([]Card, bool, error) {
+	return s.DrawWith(deckID, n, Options{}, requester)
+}
+
+// DrawInto draws n amount of cards from the deck with given deckID like Draw does and puts them on top of the pile
+// with given name, in the order they are drawn. Piles are made up by drawing into them, see validPile for names.
+// If the pile name is not valid or is BurnPile, which only Burn draws into, ErrInvalidPile is returned.
+func (s *service) DrawInto(deckID, pile string, n int, requester string) ([]Card, bool, error) {
+	if !validPile(pile) {
+		return []Card{}, false, fmt.Errorf("%w: %q", ErrInvalidPile, pile)
+	}
+
+	return s.DrawWith(deckID, n, Options{Pile: pile}, requester)
+}
+
+// DrawWith draws n amount of cards from the deck with given deckID like Draw does, choosing the cards as opts.Mode
+// says, and puts them in opts.Pile, if given, like DrawInto does.
+// In ModeSpecific, n must be the number of codes and if any of the codes does not belong to an available card,
+// ErrCardNotAvailable is returned. If the mode is unknown or codes are given in another mode, ErrInvalidMode is
+// returned.
+func (s *service) DrawWith(deckID string, n int, opts Options, requester string) ([]Card, bool, error) {
+	if 1 > n {
+		return []Card{}, false, ErrInvalidAmount
+	}
+
+	if "" != opts.Pile && !validPile(opts.Pile) {
+		return []Card{}, false, fmt.Errorf("%w: %q", ErrInvalidPile, opts.Pile)
+	}
+
+	if BurnPile == opts.Pile {
+		return []Card{}, false, fmt.Errorf("%w: %q is only drawn into by burning", ErrInvalidPile, opts.Pile)
+	}
+
+	switch opts.Mode {
+	case "", ModeTop, ModeBottom, ModeRandom:
+		if 0 < len(opts.Codes) {
+			return []Card{}, false, fmt.Errorf("%w: cards can only be given in %s mode", ErrInvalidMode, ModeSpecific)
+		}
+	case ModeSpecific:
+		if n != len(opts.Codes) {
+			return []Card{}, false, fmt.Errorf("%w: %d cards given to draw %d", ErrInvalidAmount, len(opts.Codes), n)
+		}
+	default:
+		return []Card{}, false, fmt.Errorf("%w: %q", ErrInvalidMode, opts.Mode)
+	}
+
+	deckUUID, err := uuid.Parse(deckID)
+	if err != nil {
+		return []Card{}, false, err
+	}
+
+	cutCard, err := s.r.FindCutCard(deckUUID)
+	if err != nil {
+		return []Card{}, false, err
+	}
+
+	left := 0
+	cards, err := s.r.DrawCards(deckUUID, opts.Pile, requester, func(available []Card) ([]Card, error) {
+		cards, err := s.pick(available, n, opts)
+		left = len(available) - len(cards)
+		return cards, err
+	})
+	if err != nil {
+		return []Card{}, false, err
+	}
+
+	return describe(cards), 0 < cutCard && left <= cutCard, nil
+}
+
+// pick chooses n cards among available ones, top of the deck first, as opts.Mode says
+func (s *service) pick(available []Card, n int, opts Options) ([]Card, error) {
+	if ModeSpecific == opts.Mode {
+		cards, missing := match(available, opts.Codes)
+		if 0 < len(missing) {
+			return nil, fmt.Errorf("%w: %s", ErrCardNotAvailable, strings.Join(missing, ","))
+		}
+
+		return cards, nil
+	}
+
+	if len(available) < n {
+		return nil, ErrInsufficientRemainingCard
+	}
+
+	switch opts.Mode {
+	case ModeBottom:
+		cards := make([]Card, 0, n)
+		for i := len(available) - 1; i >= len(available)-n; i-- {
+			cards = append(cards, available[i])
+		}
+
+		return cards, nil
+	case ModeRandom:
+		cards := append([]Card(nil), available...)
+		err := s.shuffler.Shuffle(len(cards), func(i, j int) {
+			cards[i], cards[j] = cards[j], cards[i]
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return cards[:n], nil
+	}
+
+	return available[:n], nil
+}
+
+// Return puts cards with given codes back to the deck with given deckID, or every drawn card if no code is given.
+// Returned cards go to the bottom of the deck in the given order, unless shuffle is set, in which case every
+// available card is shuffled; if shuffling fails, nothing is returned. The return, and the shuffle if any, are
+// recorded in the deck's history on behalf of requester. Shuffling with no cards to return reshuffles the deck.
+// Codes are matched as they are, then as card.Canonical codes, so th returns 10H. Cards in piles are drawn cards too.
+// If any of the codes does not belong to a drawn card, ErrCardNotDrawn is returned. If the deck is closed,
+// ErrDeckClosed is returned.
+func (s *service) Return(deckID string, codes []string, shuffle bool, requester string) ([]Card, error) {
+	return s.returnCards(deckID, "", shuffle, requester, func(drawn []Card) ([]Card, error) {
+		if 0 == len(codes) {
+			return drawn, nil
+		}
+
+		returned, missing := match(drawn, codes)
+		if 0 < len(missing) {
+			return nil, fmt.Errorf("%w: %s", ErrCardNotDrawn, strings.Join(missing, ","))
+		}
+
+		return returned, nil
+	})
+}
+
+// ReturnPile puts every card of the pile with given name back to the deck with given deckID like Return does, in
+// the order they were put in the pile. Returning an empty pile returns no cards.
+// If the pile name is not valid, ErrInvalidPile is returned.
+func (s *service) ReturnPile(deckID, pile string, shuffle bool, requester string) ([]Card, error) {
+	if !validPile(pile) {
+		return []Card{}, fmt.Errorf("%w: %q", ErrInvalidPile, pile)
+	}
+
+	return s.returnCards(deckID, pile, shuffle, requester, func(drawn []Card) ([]Card, error) {
+		return inPile(drawn, pile), nil
+	})
+}
+
+// Move puts cards with given codes from one pile on top of another in the given order, or every card of the pile
+// in the order they were put in it if no code is given. Codes are matched like Return does. The move is recorded in
+// the deck's history on behalf of requester.
+// If any of the pile names is not valid or both are the same, ErrInvalidPile is returned. If any of the codes does
+// not belong to a card in the pile to move from, ErrCardNotInPile is returned. If the deck is closed, ErrDeckClosed
+// is returned.
+func (s *service) Move(deckID, from, to string, codes []string, requester string) ([]Card, error) {
+	if !validPile(from) || !validPile(to) || from == to {
+		return []Card{}, fmt.Errorf("%w: can not move from %q to %q", ErrInvalidPile, from, to)
+	}
+
+	deckUUID, err := uuid.Parse(deckID)
+	if err != nil {
+		return []Card{}, err
+	}
+
+	cards, err := s.r.MoveCards(deckUUID, to, requester, func(drawn []Card) ([]Card, error) {
+		pile := inPile(drawn, from)
+		if 0 == len(codes) {
+			return pile, nil
+		}
+
+		moved, missing := match(pile, codes)
+		if 0 < len(missing) {
+			return nil, fmt.Errorf("%w: %s of %s", ErrCardNotInPile, strings.Join(missing, ","), from)
+		}
+
+		return moved, nil
+	})
+	if err != nil {
+		return []Card{}, err
+	}
+
+	return describe(cards), nil
+}
+
+// Burn draws n amount of cards from the top of the deck with given deckID into BurnPile, out of play, and returns
+// how many cards are burned, reporting if the cut card is reached like Draw does. The burn is recorded in the deck's
+// history on behalf of requester, errors are the same as Draw's.
+func (s *service) Burn(deckID string, n int, requester string) (int, bool, error) {
+	if 1 > n {
+		return 0, false, ErrInvalidAmount
+	}
+
+	deckUUID, err := uuid.Parse(deckID)
+	if err != nil {
+		return 0, false, err
+	}
+
+	cutCard, err := s.r.FindCutCard(deckUUID)
+	if err != nil {
+		return 0, false, err
+	}
+
+	left := 0
+	cards, err := s.r.BurnCards(deckUUID, requester, func(available []Card) ([]Card, error) {
+		cards, err := s.pick(available, n, Options{})
+		left = len(available) - len(cards)
+		return cards, err
+	})
+	if err != nil {
+		return 0, false, err
+	}
+
+	return len(cards), 0 < cutCard && left <= cutCard, nil
+}
+
+// Peek returns n amount of cards from the top of the deck with given deckID without drawing them. The peek is recorded
+// in the deck's history on behalf of requester, errors are the same as Draw's.
+func (s *service) Peek(deckID string, n int, requester string) ([]Card, error) {
+	if 1 > n {
+		return []Card{}, ErrInvalidAmount
+	}
+
 	deckUUID, err := uuid.Parse(deckID)
 	if err != nil {
 		return []Card{}, err
 	}
-	cards, err := s.r.FindAvailableCardByDeckID(deckUUID)
+
+	cards, err := s.r.PeekCards(deckUUID, requester, func(available []Card) ([]Card, error) {
+		return s.pick(available, n, Options{})
+	})
 	if err != nil {
 		return []Card{}, err
 	}
 
-	if len(cards) < n {
-		return []Card{}, ErrInsufficientRemainingCard
+	return describe(cards), nil
+}
+
+// Cut moves the cards above given position, counted from the top of the deck with given deckID, to the bottom of the
+// deck keeping their order, or cuts at a position chosen uniformly if position is 0, and returns the position cut at.
+// The cut is recorded in the deck's history with its position on behalf of requester.
+// If position is negative or the deck can not be cut there, i.e. position is not less than the number of available
+// cards, ErrInvalidCut is returned. If the deck is closed, ErrDeckClosed is returned.
+func (s *service) Cut(deckID string, position int, requester string) (int, error) {
+	if 0 > position {
+		return 0, fmt.Errorf("%w: %d", ErrInvalidCut, position)
+	}
+
+	deckUUID, err := uuid.Parse(deckID)
+	if err != nil {
+		return 0, err
 	}
 
-	result := cards[:n]
-	err = s.r.DrawCards(deckUUID, result...)
+	err = s.r.CutCards(deckUUID, requester, func(available []Card) ([]Card, int, error) {
+		if 2 > len(available) {
+			return nil, 0, fmt.Errorf("%w: %d cards can not be cut", ErrInvalidCut, len(available))
+		}
+
+		if 0 == position {
+			positions := make([]int, len(available)-1)
+			for i := range positions {
+				positions[i] = i + 1
+			}
+
+			err := s.shuffler.Shuffle(len(positions), func(i, j int) {
+				positions[i], positions[j] = positions[j], positions[i]
+			})
+			if err != nil {
+				return nil, 0, err
+			}
+
+			position = positions[0]
+		}
+
+		if len(available) <= position {
+			return nil, 0, fmt.Errorf("%w: %d of %d cards", ErrInvalidCut, position, len(available))
+		}
+
+		return append(append([]Card(nil), available[position:]...), available[:position]...), position, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return position, nil
+}
+
+// Deal deals n cards to each of the seats from the top of the deck with given deckID, one card at a time in the order
+// of the seats, and returns their hands, reporting if the cut card is reached like Draw does. Each hand is put on top
+// of the pile named after its seat and recorded in the deck's history on behalf of requester, all at once.
+// If there are no seats or more than MaxSeats, or seat names are not valid pile names or not distinct,
+// ErrInvalidPile is returned. If n is less than 1, ErrInvalidAmount is returned. If there are not enough available
+// cards for every hand, ErrInsufficientRemainingCard is returned. If the deck is closed, ErrDeckClosed is returned.
+func (s *service) Deal(deckID string, seats []string, n int, requester string) ([]Hand, bool, error) {
+	if 1 > n {
+		return []Hand{}, false, ErrInvalidAmount
+	}
+
+	if 0 == len(seats) || MaxSeats < len(seats) {
+		return []Hand{}, false, fmt.Errorf("%w: must deal to 1 to %d seats", ErrInvalidPile, MaxSeats)
+	}
+
+	seen := make(map[string]bool, len(seats))
+	for _, seat := range seats {
+		if !validPile(seat) || seen[seat] {
+			return []Hand{}, false, fmt.Errorf("%w: seat %q is not valid or repeated", ErrInvalidPile, seat)
+		}
+		seen[seat] = true
+	}
+
+	deckUUID, err := uuid.Parse(deckID)
+	if err != nil {
+		return []Hand{}, false, err
+	}
+
+	cutCard, err := s.r.FindCutCard(deckUUID)
+	if err != nil {
+		return []Hand{}, false, err
+	}
+
+	left := 0
+	hands, err := s.r.DealCards(deckUUID, requester, func(available []Card) ([]Hand, error) {
+		if len(available) < len(seats)*n {
+			return nil, ErrInsufficientRemainingCard
+		}
+		left = len(available) - len(seats)*n
+
+		hands := make([]Hand, len(seats))
+		for i, seat := range seats {
+			hands[i].Seat = seat
+		}
+
+		for i, c := range available[:len(seats)*n] {
+			hands[i%len(seats)].Cards = append(hands[i%len(seats)].Cards, c)
+		}
+
+		return hands, nil
+	})
+	if err != nil {
+		return []Hand{}, false, err
+	}
+
+	for i := range hands {
+		hands[i].Cards = describe(hands[i].Cards)
+	}
+
+	return hands, 0 < cutCard && left <= cutCard, nil
+}
+
+// returnCards puts the cards chosen by pick back to the deck, recording pile as the pile they are returned from
+func (s *service) returnCards(deckID, pile string, shuffle bool, requester string, pick Pick) ([]Card, error) {
+	deckUUID, err := uuid.Parse(deckID)
 	if err != nil {
 		return []Card{}, err
 	}
 
-	return result, nil
+	var returned []Card
+	pickReturned := func(drawn []Card) ([]Card, error) {
+		cards, err := pick(drawn)
+		returned = cards
+		return cards, err
+	}
+
+	arrange := func(available []Card) ([]Card, error) {
+		if shuffle {
+			err := s.shuffler.Shuffle(len(available), func(i, j int) {
+				available[i], available[j] = available[j], available[i]
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			return available, nil
+		}
+
+		isReturned := make(map[int]bool, len(returned))
+		for _, c := range returned {
+			isReturned[c.ID] = true
+		}
+
+		arranged := make([]Card, 0, len(available))
+		for _, c := range available {
+			if !isReturned[c.ID] {
+				arranged = append(arranged, c)
+			}
+		}
+
+		return append(arranged, returned...), nil
+	}
+
+	returned, err = s.r.ReturnCards(deckUUID, pile, requester, shuffle, pickReturned, arrange)
+	if err != nil {
+		return []Card{}, err
+	}
+
+	if 0 == len(returned) {
+		return []Card{}, nil
+	}
+
+	return describe(returned), nil
+}
+
+// describe returns cards with rank and color set for French playing cards, like listing does. Codes of other deck
+// types may parse as French cards too, e.g. AS is the ace of swords in tarot, so the suit has to match as well.
+func describe(cards []Card) []Card {
+	described := make([]Card, 0, len(cards))
+	for _, c := range cards {
+		if parsed, err := card.Parse(c.Code); err == nil && parsed.Suit.String() == c.Suit {
+			c.Rank, c.Color = int(parsed.Rank), parsed.Suit.Color().String()
+		}
+		described = append(described, c)
+	}
+
+	return described
+}
+
+// match returns the cards with given codes, each card matched once, and the codes no card matched. A code matches
+// a card with the code as it is first, so custom codes in lower case are found, then in upper case or as its
+// card.Canonical code.
+func match(cards []Card, codes []string) ([]Card, []string) {
+	var matched []Card
+	var missing []string
+	taken := make(map[int]bool, len(codes))
+	for _, code := range codes {
+		code = strings.TrimSpace(code)
+		upper := strings.ToUpper(code)
+		found := false
+		for _, candidate := range []string{code, upper, card.Canonical(upper)} {
+			for _, c := range cards {
+				if !taken[c.ID] && c.Code == candidate {
+					taken[c.ID], found = true, true
+					matched = append(matched, c)
+					break
+				}
+			}
+
+			if found {
+				break
+			}
+		}
+
+		if !found {
+			missing = append(missing, code)
+		}
+	}
+
+	return matched, missing
+}
+
+// inPile returns the cards in the pile with given name, keeping their order
+func inPile(cards []Card, pile string) []Card {
+	var in []Card
+	for _, c := range cards {
+		if pile == c.Pile {
+			in = append(in, c)
+		}
+	}
+
+	return in
+}
+
+// validPile reports if name is usable as a pile name: 1 to MaxPileNameLength lower case letters, digits, - or _
+func validPile(name string) bool {
+	if "" == name || MaxPileNameLength < len(name) {
+		return false
+	}
+
+	for _, r := range name {
+		if !('a' <= r && 'z' >= r) && !('0' <= r && '9' >= r) && '-' != r && '_' != r {
+			return false
+		}
+	}
+
+	return true
 }
@@ -1,10 +1,15 @@
 package drawing
 
 import (
+	"errors"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
+
+	"github.com/srgyrn/lucky-38/pkg/creating"
 )
 
 func Test_service_Draw(t *testing.T) {
@@ -58,12 +63,16 @@ func Test_service_Draw(t *testing.T) {
 					Value: "ACE",
 					Suit:  "SPADES",
 					Code:  "AS",
+					Rank:  1,
+					Color: "BLACK",
 				},
 				{
 					ID:    2,
 					Value: "2",
 					Suit:  "SPADES",
 					Code:  "2S",
+					Rank:  2,
+					Color: "BLACK",
 				},
 			},
 			wantErr: false,
@@ -89,6 +98,27 @@ func Test_service_Draw(t *testing.T) {
 			want:    []Card{},
 			wantErr: true,
 		},
+		{
+			name: "invalid amount",
+			fields: fields{
+				r: &mockRepository{
+					cards: []Card{
+						{
+							ID:    1,
+							Value: "ACE",
+							Suit:  "SPADES",
+							Code:  "AS",
+						},
+					},
+				},
+			},
+			args: args{
+				deckID: "a251071b-662f-44b6-ba11-e24863039c59",
+				n:      0,
+			},
+			want:    []Card{},
+			wantErr: true,
+		},
 		{
 			name: "deck not found",
 			fields: fields{
@@ -110,7 +140,7 @@ func Test_service_Draw(t *testing.T) {
 			s := &service{
 				r: tt.fields.r,
 			}
-			got, err := s.Draw(tt.args.deckID, tt.args.n)
+			got, _, err := s.Draw(tt.args.deckID, tt.args.n, "test")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Draw() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -123,14 +153,694 @@ func Test_service_Draw(t *testing.T) {
 }
 
 type mockRepository struct {
-	err   error
-	cards []Card
+	err      error
+	cards    []Card
+	drawn    []Card
+	arranged []Card
+	pile     string
+	cutCard  int
+	shuffled bool
+	position int
+}
+
+func (r *mockRepository) ReturnCards(_ uuid.UUID, pile, _ string, shuffled bool, pick Pick, arrange Arrange) ([]Card, error) {
+	r.pile, r.shuffled = pile, shuffled
+	if r.err != nil {
+		return []Card{}, r.err
+	}
+
+	cards, err := pick(r.drawn)
+	if err != nil {
+		return []Card{}, err
+	}
+
+	r.arranged, err = arrange(append(r.cards, cards...))
+	return cards, err
+}
+
+func (r *mockRepository) DrawCards(_ uuid.UUID, pile, _ string, pick Pick) ([]Card, error) {
+	r.pile = pile
+	if r.err != nil {
+		return []Card{}, r.err
+	}
+
+	return pick(r.cards)
+}
+
+func (r *mockRepository) DealCards(_ uuid.UUID, _ string, split Split) ([]Hand, error) {
+	if r.err != nil {
+		return []Hand{}, r.err
+	}
+
+	return split(r.cards)
+}
+
+func (r *mockRepository) BurnCards(_ uuid.UUID, _ string, pick Pick) ([]Card, error) {
+	r.pile = BurnPile
+	if r.err != nil {
+		return []Card{}, r.err
+	}
+
+	return pick(r.cards)
+}
+
+func (r *mockRepository) PeekCards(_ uuid.UUID, _ string, pick Pick) ([]Card, error) {
+	if r.err != nil {
+		return []Card{}, r.err
+	}
+
+	return pick(r.cards)
+}
+
+func (r *mockRepository) CutCards(_ uuid.UUID, _ string, rotate Rotate) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	cards, position, err := rotate(r.cards)
+	r.arranged, r.position = cards, position
+	return err
+}
+
+func (r *mockRepository) FindCutCard(_ uuid.UUID) (int, error) {
+	return r.cutCard, nil
+}
+
+func (r *mockRepository) MoveCards(_ uuid.UUID, pile, _ string, pick Pick) ([]Card, error) {
+	r.pile = pile
+	if r.err != nil {
+		return []Card{}, r.err
+	}
+
+	return pick(r.drawn)
+}
+
+func Test_service_Return(t *testing.T) {
+	available := []Card{
+		{ID: 3, Value: "3", Suit: "SPADES", Code: "3S", Rank: 3, Color: "BLACK"},
+		{ID: 4, Value: "4", Suit: "SPADES", Code: "4S", Rank: 4, Color: "BLACK"},
+	}
+	drawn := []Card{
+		{ID: 1, Value: "ACE", Suit: "SPADES", Code: "AS", Rank: 1, Color: "BLACK"},
+		{ID: 2, Value: "2", Suit: "SPADES", Code: "2S", Rank: 2, Color: "BLACK"},
+	}
+
+	tests := []struct {
+		name         string
+		codes        []string
+		shuffle      bool
+		err          error
+		want         []Card
+		wantArranged []Card
+		wantErr      error
+	}{
+		{
+			name:         "all drawn cards",
+			want:         drawn,
+			wantArranged: append(append([]Card{}, available...), drawn...),
+		},
+		{
+			name:  "specific cards",
+			codes: []string{"2s"},
+			want:  drawn[1:],
+			wantArranged: []Card{
+				available[0],
+				available[1],
+				drawn[1],
+			},
+		},
+		{
+			name:  "symbol codes",
+			codes: []string{"2♠", "a♤"},
+			want:  []Card{drawn[1], drawn[0]},
+			wantArranged: []Card{
+				available[0],
+				available[1],
+				drawn[1],
+				drawn[0],
+			},
+		},
+		{
+			name:    "card not drawn",
+			codes:   []string{"2S", "3S"},
+			want:    []Card{},
+			wantErr: ErrCardNotDrawn,
+		},
+		{
+			name:    "deck not found",
+			err:     ErrNotFound,
+			want:    []Card{},
+			wantErr: ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &mockRepository{
+				err:   tt.err,
+				cards: append([]Card{}, available...),
+				drawn: append([]Card{}, drawn...),
+			}
+			s := &service{r: r}
+			got, err := s.Return("a251071b-662f-44b6-ba11-e24863039c59", tt.codes, tt.shuffle, "test")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Return() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Return() got = %v, want %v", got, tt.want)
+			}
+
+			if !reflect.DeepEqual(r.arranged, tt.wantArranged) {
+				t.Errorf("Return() arranged = %v, want %v", r.arranged, tt.wantArranged)
+			}
+		})
+	}
+
+	t.Run("shuffled", func(t *testing.T) {
+		r := &mockRepository{
+			cards: append([]Card{}, available...),
+			drawn: append([]Card{}, drawn...),
+		}
+		s := &service{r: r, shuffler: creating.CryptoShuffler{}}
+		if _, err := s.Return("a251071b-662f-44b6-ba11-e24863039c59", nil, true, "test"); err != nil {
+			t.Fatalf("Return() error = %v", err)
+		}
+
+		sort.Slice(r.arranged, func(i, j int) bool {
+			return r.arranged[i].ID < r.arranged[j].ID
+		})
+		want := append(append([]Card{}, drawn...), available...)
+		if !reflect.DeepEqual(r.arranged, want) || !r.shuffled {
+			t.Errorf("Return() arranged = %v, shuffled = %v, want every card of %v shuffled", r.arranged, r.shuffled, want)
+		}
+	})
+
+	t.Run("shuffled without cards to return", func(t *testing.T) {
+		r := &mockRepository{cards: append([]Card{}, available...)}
+		s := &service{r: r, shuffler: reverseShuffler{}}
+		got, err := s.Return("a251071b-662f-44b6-ba11-e24863039c59", nil, true, "test")
+		if err != nil {
+			t.Fatalf("Return() error = %v", err)
+		}
+
+		if want := []Card{available[1], available[0]}; !reflect.DeepEqual(got, []Card{}) || !reflect.DeepEqual(r.arranged, want) || !r.shuffled {
+			t.Errorf("Return() got = %v, arranged = %v, shuffled = %v, want [], %v shuffled", got, r.arranged, r.shuffled, want)
+		}
+	})
+}
+
+func Test_describe(t *testing.T) {
+	cards := []Card{
+		{ID: 1, Code: "10H", Value: "10", Suit: "HEARTS"},
+		{ID: 2, Code: "AS", Value: "ACE", Suit: "SWORDS"},
+		{ID: 3, Code: "WB", Value: "WILD", Suit: "BLUE"},
+	}
+
+	want := []Card{
+		{ID: 1, Code: "10H", Value: "10", Suit: "HEARTS", Rank: 10, Color: "RED"},
+		cards[1],
+		cards[2],
+	}
+	if got := describe(cards); !reflect.DeepEqual(got, want) {
+		t.Errorf("describe() = %v, want %v", got, want)
+	}
+}
+
+func Test_service_DrawInto(t *testing.T) {
+	cards := []Card{
+		{ID: 1, Value: "ACE", Suit: "SPADES", Code: "AS", Rank: 1, Color: "BLACK"},
+		{ID: 2, Value: "2", Suit: "SPADES", Code: "2S", Rank: 2, Color: "BLACK"},
+	}
+
+	tests := []struct {
+		name     string
+		pile     string
+		want     []Card
+		wantPile string
+		wantErr  error
+	}{
+		{name: "valid", pile: "player-1", want: cards[:1], wantPile: "player-1"},
+		{name: "empty pile name", pile: "", want: []Card{}, wantErr: ErrInvalidPile},
+		{name: "upper case pile name", pile: "Dealer", want: []Card{}, wantErr: ErrInvalidPile},
+		{name: "long pile name", pile: strings.Repeat("p", MaxPileNameLength+1), want: []Card{}, wantErr: ErrInvalidPile},
+		{name: "burn pile", pile: BurnPile, want: []Card{}, wantErr: ErrInvalidPile},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &mockRepository{cards: cards}
+			s := &service{r: r}
+			got, _, err := s.DrawInto("a251071b-662f-44b6-ba11-e24863039c59", tt.pile, 1, "test")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("DrawInto() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) || r.pile != tt.wantPile {
+				t.Errorf("DrawInto() got = %v into %q, want %v into %q", got, r.pile, tt.want, tt.wantPile)
+			}
+		})
+	}
+}
+
+func Test_service_ReturnPile(t *testing.T) {
+	available := []Card{{ID: 3, Value: "3", Suit: "SPADES", Code: "3S", Rank: 3, Color: "BLACK"}}
+	drawn := []Card{
+		{ID: 1, Value: "ACE", Suit: "SPADES", Code: "AS", Rank: 1, Color: "BLACK", Pile: "dealer"},
+		{ID: 2, Value: "2", Suit: "SPADES", Code: "2S", Rank: 2, Color: "BLACK"},
+		{ID: 4, Value: "4", Suit: "SPADES", Code: "4S", Rank: 4, Color: "BLACK", Pile: "dealer"},
+	}
+
+	r := &mockRepository{cards: append([]Card{}, available...), drawn: drawn}
+	s := &service{r: r}
+	got, err := s.ReturnPile("a251071b-662f-44b6-ba11-e24863039c59", "dealer", false, "test")
+	if err != nil {
+		t.Fatalf("ReturnPile() error = %v", err)
+	}
+
+	want := []Card{drawn[0], drawn[2]}
+	if !reflect.DeepEqual(got, want) || "dealer" != r.pile {
+		t.Errorf("ReturnPile() got = %v from %q, want %v from dealer", got, r.pile, want)
+	}
+
+	if wantArranged := append(append([]Card{}, available...), want...); !reflect.DeepEqual(r.arranged, wantArranged) {
+		t.Errorf("ReturnPile() arranged = %v, want %v", r.arranged, wantArranged)
+	}
+
+	got, err = s.ReturnPile("a251071b-662f-44b6-ba11-e24863039c59", "player1", false, "test")
+	if err != nil || 0 != len(got) {
+		t.Errorf("ReturnPile() of an empty pile = %v, %v, want no cards", got, err)
+	}
+
+	if _, err := s.ReturnPile("a251071b-662f-44b6-ba11-e24863039c59", "", false, "test"); !errors.Is(err, ErrInvalidPile) {
+		t.Errorf("ReturnPile() error = %v, want %v", err, ErrInvalidPile)
+	}
 }
 
-func (r *mockRepository) DrawCards(uuid.UUID, ...Card) error {
-	return r.err
+func Test_service_Move(t *testing.T) {
+	drawn := []Card{
+		{ID: 1, Value: "ACE", Suit: "SPADES", Code: "AS", Rank: 1, Color: "BLACK", Pile: "player1"},
+		{ID: 2, Value: "2", Suit: "SPADES", Code: "2S", Rank: 2, Color: "BLACK"},
+		{ID: 3, Value: "3", Suit: "SPADES", Code: "3S", Rank: 3, Color: "BLACK", Pile: "player1"},
+		{ID: 4, Value: "4", Suit: "SPADES", Code: "4S", Rank: 4, Color: "BLACK", Pile: "dealer"},
+	}
+
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		codes   []string
+		err     error
+		want    []Card
+		wantErr error
+	}{
+		{name: "whole pile", from: "player1", to: "discard", want: []Card{drawn[0], drawn[2]}},
+		{name: "specific cards", from: "player1", to: "discard", codes: []string{"3s", "A♠"}, want: []Card{drawn[2], drawn[0]}},
+		{name: "empty pile", from: "player2", to: "discard", want: []Card{}},
+		{name: "card in another pile", from: "player1", to: "discard", codes: []string{"4S"}, want: []Card{}, wantErr: ErrCardNotInPile},
+		{name: "drawn card out of piles", from: "player1", to: "discard", codes: []string{"2S"}, want: []Card{}, wantErr: ErrCardNotInPile},
+		{name: "same pile", from: "player1", to: "player1", want: []Card{}, wantErr: ErrInvalidPile},
+		{name: "invalid pile", from: "player1", to: "Discard", want: []Card{}, wantErr: ErrInvalidPile},
+		{name: "deck not found", from: "player1", to: "discard", err: ErrNotFound, want: []Card{}, wantErr: ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &mockRepository{err: tt.err, drawn: drawn}
+			s := &service{r: r}
+			got, err := s.Move("a251071b-662f-44b6-ba11-e24863039c59", tt.from, tt.to, tt.codes, "test")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Move() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Move() got = %v, want %v", got, tt.want)
+			}
+
+			if nil == tt.wantErr && tt.to != r.pile {
+				t.Errorf("Move() moved to %q, want %q", r.pile, tt.to)
+			}
+		})
+	}
 }
 
-func (r *mockRepository) FindAvailableCardByDeckID(uuid.UUID) ([]Card, error) {
-	return r.cards, r.err
+func Test_service_Deal(t *testing.T) {
+	var cards []Card
+	for i, code := range []string{"AS", "2S", "3S", "4S", "5S", "6S", "7S"} {
+		cards = append(cards, Card{ID: i + 1, Code: code})
+	}
+
+	tests := []struct {
+		name    string
+		seats   []string
+		n       int
+		err     error
+		want    []Hand
+		wantErr error
+	}{
+		{
+			name:  "round robin",
+			seats: []string{"north", "east", "south"},
+			n:     2,
+			want: []Hand{
+				{Seat: "north", Cards: []Card{cards[0], cards[3]}},
+				{Seat: "east", Cards: []Card{cards[1], cards[4]}},
+				{Seat: "south", Cards: []Card{cards[2], cards[5]}},
+			},
+		},
+		{
+			name:  "players",
+			seats: Seats(2),
+			n:     1,
+			want: []Hand{
+				{Seat: "player1", Cards: []Card{cards[0]}},
+				{Seat: "player2", Cards: []Card{cards[1]}},
+			},
+		},
+		{name: "not enough cards", seats: Seats(4), n: 2, want: []Hand{}, wantErr: ErrInsufficientRemainingCard},
+		{name: "invalid amount", seats: Seats(2), n: 0, want: []Hand{}, wantErr: ErrInvalidAmount},
+		{name: "no seats", n: 1, want: []Hand{}, wantErr: ErrInvalidPile},
+		{name: "repeated seats", seats: []string{"dealer", "dealer"}, n: 1, want: []Hand{}, wantErr: ErrInvalidPile},
+		{name: "invalid seat", seats: []string{"North"}, n: 1, want: []Hand{}, wantErr: ErrInvalidPile},
+		{name: "deck not found", seats: Seats(2), n: 1, err: ErrNotFound, want: []Hand{}, wantErr: ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{r: &mockRepository{err: tt.err, cards: cards}}
+			got, _, err := s.Deal("a251071b-662f-44b6-ba11-e24863039c59", tt.seats, tt.n, "test")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Deal() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Deal() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// reverseShuffler puts elements in reverse order
+type reverseShuffler struct{}
+
+func (reverseShuffler) Shuffle(n int, swap func(i, j int)) error {
+	for i := 0; i < n/2; i++ {
+		swap(i, n-1-i)
+	}
+
+	return nil
+}
+
+func Test_service_DrawWith_customCodes(t *testing.T) {
+	cards := []Card{{ID: 1, Code: "BR"}, {ID: 2, Code: "br"}, {ID: 3, Code: "AS"}}
+
+	tests := []struct {
+		name    string
+		codes   []string
+		want    []Card
+		wantErr error
+	}{
+		{name: "lower case custom code", codes: []string{"br"}, want: []Card{cards[1]}},
+		{name: "upper case custom code", codes: []string{" BR"}, want: []Card{cards[0]}},
+		{name: "french code in lower case", codes: []string{"as"}, want: []Card{cards[2]}},
+		{name: "both custom codes", codes: []string{"br", "br"}, want: []Card{cards[1], cards[0]}},
+		{name: "custom code not available", codes: []string{"bx"}, want: []Card{}, wantErr: ErrCardNotAvailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{r: &mockRepository{cards: cards}}
+			got, _, err := s.DrawWith("a251071b-662f-44b6-ba11-e24863039c59", len(tt.codes), Options{Mode: ModeSpecific, Codes: tt.codes}, "test")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("DrawWith() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DrawWith() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// failingShuffler fails to shuffle
+type failingShuffler struct{}
+
+func (failingShuffler) Shuffle(int, func(i, j int)) error {
+	return errors.New("no randomness")
+}
+
+func Test_service_Return_shuffler(t *testing.T) {
+	available := []Card{{ID: 3, Code: "3S"}, {ID: 4, Code: "4S"}}
+	drawn := []Card{{ID: 1, Code: "AS"}, {ID: 2, Code: "2S"}}
+
+	tests := []struct {
+		name         string
+		shuffler     creating.Shuffler
+		wantArranged []Card
+		wantErr      bool
+	}{
+		{name: "shuffles with the shuffler", shuffler: reverseShuffler{}, wantArranged: []Card{drawn[1], drawn[0], available[1], available[0]}},
+		{name: "shuffler fails", shuffler: failingShuffler{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &mockRepository{cards: append([]Card{}, available...), drawn: append([]Card{}, drawn...)}
+			_, err := (&service{r: r, shuffler: tt.shuffler}).Return("a251071b-662f-44b6-ba11-e24863039c59", nil, true, "test")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Return() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(r.arranged, tt.wantArranged) {
+				t.Errorf("Return() arranged = %v, want %v", r.arranged, tt.wantArranged)
+			}
+		})
+	}
+}
+
+func Test_service_DrawWith(t *testing.T) {
+	var cards []Card
+	for i, code := range []string{"AS", "2S", "3S", "4S", "10S"} {
+		cards = append(cards, Card{ID: i + 1, Code: code})
+	}
+
+	tests := []struct {
+		name    string
+		n       int
+		opts    Options
+		want    []Card
+		wantErr error
+	}{
+		{name: "top by default", n: 2, want: cards[:2]},
+		{name: "top", n: 2, opts: Options{Mode: ModeTop}, want: cards[:2]},
+		{name: "bottom", n: 2, opts: Options{Mode: ModeBottom}, want: []Card{cards[4], cards[3]}},
+		{name: "random", n: 2, opts: Options{Mode: ModeRandom}, want: []Card{cards[4], cards[3]}},
+		{name: "specific", n: 2, opts: Options{Mode: ModeSpecific, Codes: []string{"ts", "2s"}}, want: []Card{cards[4], cards[1]}},
+		{name: "specific card not available", n: 2, opts: Options{Mode: ModeSpecific, Codes: []string{"AS", "KH"}}, want: []Card{}, wantErr: ErrCardNotAvailable},
+		{name: "specific card twice", n: 2, opts: Options{Mode: ModeSpecific, Codes: []string{"AS", "AS"}}, want: []Card{}, wantErr: ErrCardNotAvailable},
+		{name: "specific amount mismatch", n: 1, opts: Options{Mode: ModeSpecific, Codes: []string{"AS", "2S"}}, want: []Card{}, wantErr: ErrInvalidAmount},
+		{name: "codes in another mode", n: 1, opts: Options{Mode: ModeBottom, Codes: []string{"AS"}}, want: []Card{}, wantErr: ErrInvalidMode},
+		{name: "unknown mode", n: 1, opts: Options{Mode: "middle"}, want: []Card{}, wantErr: ErrInvalidMode},
+		{name: "bottom exceeds remaining", n: 6, opts: Options{Mode: ModeBottom}, want: []Card{}, wantErr: ErrInsufficientRemainingCard},
+		{name: "invalid pile", n: 1, opts: Options{Pile: "Dealer"}, want: []Card{}, wantErr: ErrInvalidPile},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{r: &mockRepository{cards: cards}, shuffler: reverseShuffler{}}
+			got, _, err := s.DrawWith("a251071b-662f-44b6-ba11-e24863039c59", tt.n, tt.opts, "test")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("DrawWith() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DrawWith() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_service_Burn(t *testing.T) {
+	cards := []Card{{ID: 1, Code: "AS"}, {ID: 2, Code: "2S"}, {ID: 3, Code: "3S"}}
+
+	tests := []struct {
+		name    string
+		n       int
+		err     error
+		want    int
+		wantErr error
+	}{
+		{name: "valid", n: 2, want: 2},
+		{name: "exceeds remaining", n: 4, wantErr: ErrInsufficientRemainingCard},
+		{name: "invalid amount", n: 0, wantErr: ErrInvalidAmount},
+		{name: "closed deck", n: 1, err: ErrDeckClosed, wantErr: ErrDeckClosed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &mockRepository{err: tt.err, cards: cards}
+			got, _, err := (&service{r: r}).Burn("a251071b-662f-44b6-ba11-e24863039c59", tt.n, "test")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Burn() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("Burn() got = %d, want %d", got, tt.want)
+			}
+
+			if nil == tt.wantErr && BurnPile != r.pile {
+				t.Errorf("Burn() burned into %q, want %q", r.pile, BurnPile)
+			}
+		})
+	}
+}
+
+func Test_service_Peek(t *testing.T) {
+	cards := []Card{{ID: 1, Code: "AS"}, {ID: 2, Code: "2S"}, {ID: 3, Code: "3S"}}
+
+	tests := []struct {
+		name    string
+		n       int
+		err     error
+		want    []Card
+		wantErr error
+	}{
+		{name: "valid", n: 2, want: cards[:2]},
+		{name: "exceeds remaining", n: 4, want: []Card{}, wantErr: ErrInsufficientRemainingCard},
+		{name: "invalid amount", n: 0, want: []Card{}, wantErr: ErrInvalidAmount},
+		{name: "deck not found", n: 1, err: ErrNotFound, want: []Card{}, wantErr: ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := (&service{r: &mockRepository{err: tt.err, cards: cards}}).Peek("a251071b-662f-44b6-ba11-e24863039c59", tt.n, "test")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Peek() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Peek() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_service_Draw_cutCard(t *testing.T) {
+	cards := []Card{{ID: 1, Code: "AS"}, {ID: 2, Code: "2S"}, {ID: 3, Code: "3S"}, {ID: 4, Code: "4S"}, {ID: 5, Code: "5S"}}
+
+	tests := []struct {
+		name    string
+		cutCard int
+		n       int
+		want    bool
+	}{
+		{name: "no cut card", n: 5},
+		{name: "before cut card", cutCard: 2, n: 2},
+		{name: "at cut card", cutCard: 2, n: 3, want: true},
+		{name: "past cut card", cutCard: 2, n: 4, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{r: &mockRepository{cards: cards, cutCard: tt.cutCard}}
+			got, reached, err := s.Draw("a251071b-662f-44b6-ba11-e24863039c59", tt.n, "test")
+			if err != nil {
+				t.Fatalf("Draw() error = %v", err)
+			}
+
+			if tt.n != len(got) || reached != tt.want {
+				t.Errorf("Draw() got %d cards, reached = %v, want %d cards, reached = %v", len(got), reached, tt.n, tt.want)
+			}
+		})
+	}
+}
+
+func Test_service_Deal_cutCard(t *testing.T) {
+	cards := []Card{{ID: 1, Code: "AS"}, {ID: 2, Code: "2S"}, {ID: 3, Code: "3S"}, {ID: 4, Code: "4S"}, {ID: 5, Code: "5S"}}
+
+	tests := []struct {
+		name    string
+		cutCard int
+		n       int
+		want    bool
+	}{
+		{name: "no cut card", n: 2},
+		{name: "before cut card", cutCard: 2, n: 1},
+		{name: "past cut card", cutCard: 2, n: 2, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{r: &mockRepository{cards: cards, cutCard: tt.cutCard}}
+			got, reached, err := s.Deal("a251071b-662f-44b6-ba11-e24863039c59", Seats(2), tt.n, "test")
+			if err != nil {
+				t.Fatalf("Deal() error = %v", err)
+			}
+
+			if 2 != len(got) || reached != tt.want {
+				t.Errorf("Deal() got %d hands, reached = %v, want 2 hands, reached = %v", len(got), reached, tt.want)
+			}
+		})
+	}
+}
+
+func Test_service_Burn_cutCard(t *testing.T) {
+	cards := []Card{{ID: 1, Code: "AS"}, {ID: 2, Code: "2S"}, {ID: 3, Code: "3S"}, {ID: 4, Code: "4S"}, {ID: 5, Code: "5S"}}
+
+	tests := []struct {
+		name    string
+		cutCard int
+		n       int
+		want    bool
+	}{
+		{name: "no cut card", n: 5},
+		{name: "before cut card", cutCard: 2, n: 2},
+		{name: "at cut card", cutCard: 2, n: 3, want: true},
+		{name: "past cut card", cutCard: 2, n: 4, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{r: &mockRepository{cards: cards, cutCard: tt.cutCard}}
+			got, reached, err := s.Burn("a251071b-662f-44b6-ba11-e24863039c59", tt.n, "test")
+			if err != nil {
+				t.Fatalf("Burn() error = %v", err)
+			}
+
+			if tt.n != got || reached != tt.want {
+				t.Errorf("Burn() got %d cards, reached = %v, want %d cards, reached = %v", got, reached, tt.n, tt.want)
+			}
+		})
+	}
+}
+
+func Test_service_Cut(t *testing.T) {
+	cards := []Card{{ID: 1, Code: "AS"}, {ID: 2, Code: "2S"}, {ID: 3, Code: "3S"}, {ID: 4, Code: "4S"}}
+
+	tests := []struct {
+		name     string
+		cards    []Card
+		position int
+		err      error
+		want     int
+		wantDeck []Card
+		wantErr  error
+	}{
+		{name: "at position", cards: cards, position: 1, want: 1, wantDeck: []Card{cards[1], cards[2], cards[3], cards[0]}},
+		{name: "at random", cards: cards, want: 3, wantDeck: []Card{cards[3], cards[0], cards[1], cards[2]}},
+		{name: "at bottom", cards: cards, position: 4, wantErr: ErrInvalidCut},
+		{name: "negative position", cards: cards, position: -1, wantErr: ErrInvalidCut},
+		{name: "single card", cards: cards[:1], wantErr: ErrInvalidCut},
+		{name: "closed deck", cards: cards, position: 1, err: ErrDeckClosed, wantErr: ErrDeckClosed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &mockRepository{err: tt.err, cards: tt.cards}
+			got, err := (&service{r: r, shuffler: reverseShuffler{}}).Cut("a251071b-662f-44b6-ba11-e24863039c59", tt.position, "test")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Cut() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want || r.position != tt.want || !reflect.DeepEqual(r.arranged, tt.wantDeck) {
+				t.Errorf("Cut() got = %d, deck %v cut at %d, want %d, deck %v", got, r.arranged, r.position, tt.want, tt.wantDeck)
+			}
+		})
+	}
 }
@@ -0,0 +1,84 @@
+package history
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// Draw is a persisted draw from a deck, a return to it, a move between its piles, a hand dealt from it, a burn,
+	// a peek at its top cards, a cut or a reshuffle as told by Action. Cuts and shuffles have no cards. Number orders
+	// the draws of a deck, starting from 1. Pile names the pile cards are drawn into, moved to, dealt to, burned into
+	// or returned from, it is omitted if there is none. Position is the number of cards a cut moves from the top of
+	// the deck to the bottom, it is omitted for other actions.
+	Draw struct {
+		ID        uuid.UUID `json:"draw_id"`
+		DeckID    uuid.UUID `json:"deck_id"`
+		Number    int       `json:"number"`
+		Action    string    `json:"action"`
+		Pile      string    `json:"pile,omitempty"`
+		Position  int       `json:"position,omitempty"`
+		Requester string    `json:"requester"`
+		DrawnAt   time.Time `json:"drawn_at"`
+		Cards     []Card    `json:"cards"`
+	}
+
+	// Card is a card as it was dealt. Copy tells which deck of a shoe the card comes from, it is omitted for
+	// single decks. Reversed tells if the card was upside down.
+	Card struct {
+		ID       int    `json:"-"`
+		Code     string `json:"code"`
+		Value    string `json:"value"`
+		Suit     string `json:"suit"`
+		Copy     int    `json:"copy,omitempty"`
+		Reversed bool   `json:"reversed,omitempty"`
+	}
+
+	Service interface {
+		Draws(deckID string) ([]Draw, error)
+	}
+
+	Repository interface {
+		FindDraws(deckID uuid.UUID) ([]Draw, error)
+	}
+
+	service struct {
+		r Repository
+	}
+)
+
+// Actions recorded in the history of a deck
+const (
+	ActionDraw    = "draw"
+	ActionReturn  = "return"
+	ActionMove    = "move"
+	ActionDeal    = "deal"
+	ActionBurn    = "burn"
+	ActionPeek    = "peek"
+	ActionCut     = "cut"
+	ActionShuffle = "shuffle"
+)
+
+var ErrNotFound = errors.New("deck not found")
+
+func NewService(r Repository) Service {
+	return &service{r: r}
+}
+
+// Draws returns every action taken on the cards of the deck with given ID in the order they took place, with cards in
+// the order they were dealt. If deck is not found, ErrNotFound is returned.
+func (s *service) Draws(deckID string) ([]Draw, error) {
+	deckUUID, err := uuid.Parse(deckID)
+	if err != nil {
+		return []Draw{}, ErrNotFound
+	}
+
+	draws, err := s.r.FindDraws(deckUUID)
+	if err != nil {
+		return []Draw{}, err
+	}
+
+	return draws, nil
+}
@@ -0,0 +1,80 @@
+package history
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func Test_service_Draws(t *testing.T) {
+	deckID, _ := uuid.Parse("a251071b-662f-44b6-ba11-e24863039c59")
+	drawID, _ := uuid.Parse("69077400-88cd-11eb-8dcd-0242ac130003")
+	draws := []Draw{
+		{
+			ID:        drawID,
+			DeckID:    deckID,
+			Number:    1,
+			Requester: "dealer",
+			DrawnAt:   time.Date(2021, 3, 20, 10, 0, 0, 0, time.UTC),
+			Cards: []Card{
+				{ID: 2, Code: "2S", Value: "2", Suit: "SPADES"},
+				{ID: 1, Code: "AS", Value: "ACE", Suit: "SPADES"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		r       Repository
+		deckID  string
+		want    []Draw
+		wantErr error
+	}{
+		{
+			name:    "invalid id",
+			r:       &mockRepository{},
+			deckID:  "asdf",
+			want:    []Draw{},
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "deck not found",
+			r:       &mockRepository{err: ErrNotFound},
+			deckID:  deckID.String(),
+			want:    []Draw{},
+			wantErr: ErrNotFound,
+		},
+		{
+			name:   "valid",
+			r:      &mockRepository{draws: draws},
+			deckID: deckID.String(),
+			want:   draws,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewService(tt.r)
+			got, err := s.Draws(tt.deckID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Draws() error = %v, want %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Draws() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type mockRepository struct {
+	err   error
+	draws []Draw
+}
+
+func (r *mockRepository) FindDraws(uuid.UUID) ([]Draw, error) {
+	return r.draws, r.err
+}
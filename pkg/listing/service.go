@@ -4,29 +4,67 @@ import (
 	"errors"
 
 	"github.com/google/uuid"
+
+	"github.com/srgyrn/lucky-38/pkg/card"
 )
 
 type (
+	// Deck is a deck with counts of its available cards. Cards, top of the deck first, are only listed on demand as
+	// they tell what is going to be dealt. CutCard is the number of available cards left when the cut card is reached,
+	// it is omitted if the deck has no cut card.
 	Deck struct {
-		ID        uuid.UUID `json:"deck_id"`
-		Shuffled  bool      `json:"shuffled"`
-		Remaining int       `json:"remaining"`
-		Cards     []Card    `json:"cards"`
+		ID         uuid.UUID `json:"deck_id"`
+		Shuffled   bool      `json:"shuffled"`
+		Remaining  int       `json:"remaining"`
+		Seed       *int64    `json:"seed,omitempty"`
+		Commitment string    `json:"commitment,omitempty"`
+		Closed     bool      `json:"closed"`
+		CutCard    int       `json:"cut_card,omitempty"`
+		Counts     Counts    `json:"counts"`
+		Cards      []Card    `json:"cards,omitempty"`
+		Piles      Piles     `json:"piles,omitempty"`
+	}
+
+	// Counts has the number of available cards of a deck by suit and by value, e.g. SPADES and ACE
+	Counts struct {
+		Suits map[string]int `json:"suits"`
+		Ranks map[string]int `json:"ranks"`
 	}
 
+	// Piles has the number of cards in each pile of a deck by pile name
+	Piles map[string]int
+
+	// Pile is a named pile of cards drawn from a deck, cards in the order they were put in the pile
+	Pile struct {
+		DeckID uuid.UUID `json:"deck_id"`
+		Name   string    `json:"name"`
+		Cards  []Card    `json:"cards"`
+	}
+
+	// Card is a card of a deck. Copy tells which deck of a shoe the card comes from, it is omitted for single decks.
+	// Reversed tells if the card is upside down in a deck created with reversals.
+	// Rank, from 1 for ace to 13 for king, and Color are set for French playing cards only.
 	Card struct {
-		ID    int    `json:"-"`
-		Code  string `json:"code"`
-		Value string `json:"value"`
-		Suit  string `json:"suit"`
+		ID       int    `json:"-"`
+		Code     string `json:"code"`
+		Value    string `json:"value"`
+		Suit     string `json:"suit"`
+		Rank     int    `json:"rank,omitempty"`
+		Color    string `json:"color,omitempty"`
+		Copy     int    `json:"copy,omitempty"`
+		Reversed bool   `json:"reversed,omitempty"`
 	}
 
 	Service interface {
-		List(ID string) (Deck, error)
+		List(ID string, showCards bool) (Deck, error)
+		ListPile(ID, name string) (Pile, error)
 	}
 
 	Repository interface {
 		Find(ID uuid.UUID) (Deck, error)
+		// FindPile returns cards in the pile with given name of the deck with given ID, in the order they were put
+		// in the pile. If the deck is not found, ErrNotFound is returned.
+		FindPile(ID uuid.UUID, name string) ([]Card, error)
 	}
 
 	service struct {
@@ -40,9 +78,10 @@ func NewService(r Repository) Service {
 	return &service{r: r}
 }
 
-// List uses Repository to retrieve Deck by given deck id from DB.
+// List uses Repository to retrieve Deck by given deck id from DB with counts of its available cards. Cards are left
+// out unless showCards is set; listed cards that are French playing cards have their rank and color.
 // If deck is not found, ErrNotFound is returned.
-func (s *service) List(ID string) (Deck, error) {
+func (s *service) List(ID string, showCards bool) (Deck, error) {
 	deckID, err := uuid.Parse(ID)
 	if err != nil {
 		return Deck{}, err
@@ -51,5 +90,47 @@ func (s *service) List(ID string) (Deck, error) {
 	if err != nil {
 		return Deck{}, ErrNotFound
 	}
+
+	deck.Counts = Counts{Suits: make(map[string]int), Ranks: make(map[string]int)}
+	for i := range deck.Cards {
+		deck.Cards[i].describe()
+		deck.Counts.Suits[deck.Cards[i].Suit]++
+		deck.Counts.Ranks[deck.Cards[i].Value]++
+	}
+
+	if !showCards {
+		deck.Cards = nil
+	}
+
 	return deck, nil
 }
+
+// describe sets rank and color of the card if it is a French playing card. Codes of other deck types may parse as
+// French cards too, e.g. AS is the ace of swords in tarot, so the suit has to match as well.
+func (c *Card) describe() {
+	if parsed, err := card.Parse(c.Code); err == nil && parsed.Suit.String() == c.Suit {
+		c.Rank, c.Color = int(parsed.Rank), parsed.Suit.Color().String()
+	}
+}
+
+// ListPile returns the pile with given name of the deck with given ID. A pile nothing is put in has no cards.
+// If deck is not found, ErrNotFound is returned.
+func (s *service) ListPile(ID, name string) (Pile, error) {
+	deckID, err := uuid.Parse(ID)
+	if err != nil {
+		return Pile{}, ErrNotFound
+	}
+
+	cards, err := s.r.FindPile(deckID, name)
+	if err != nil {
+		return Pile{}, err
+	}
+
+	pile := Pile{DeckID: deckID, Name: name, Cards: []Card{}}
+	for _, c := range cards {
+		c.describe()
+		pile.Cards = append(pile.Cards, c)
+	}
+
+	return pile, nil
+}
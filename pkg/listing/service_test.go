@@ -0,0 +1,156 @@
+package listing
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func Test_service_List(t *testing.T) {
+	deckID, _ := uuid.Parse("a251071b-662f-44b6-ba11-e24863039c59")
+	deck := Deck{ID: deckID, Remaining: 4, Cards: []Card{
+		{Code: "AS", Value: "ACE", Suit: "SPADES"},
+		{Code: "10H", Value: "10", Suit: "HEARTS"},
+		{Code: "AS", Value: "ACE", Suit: "SWORDS"},
+		{Code: "X1", Value: "JOKER", Suit: "NONE"},
+	}}
+
+	counts := Counts{
+		Suits: map[string]int{"SPADES": 1, "HEARTS": 1, "SWORDS": 1, "NONE": 1},
+		Ranks: map[string]int{"ACE": 2, "10": 1, "JOKER": 1},
+	}
+
+	tests := []struct {
+		name      string
+		r         Repository
+		id        string
+		showCards bool
+		want      Deck
+		wantErr   error
+	}{
+		{
+			name:      "describes french cards",
+			r:         &mockRepository{deck: deck},
+			id:        deckID.String(),
+			showCards: true,
+			want: Deck{ID: deckID, Remaining: 4, Counts: counts, Cards: []Card{
+				{Code: "AS", Value: "ACE", Suit: "SPADES", Rank: 1, Color: "BLACK"},
+				{Code: "10H", Value: "10", Suit: "HEARTS", Rank: 10, Color: "RED"},
+				{Code: "AS", Value: "ACE", Suit: "SWORDS"},
+				{Code: "X1", Value: "JOKER", Suit: "NONE"},
+			}},
+		},
+		{
+			name: "hides cards",
+			r:    &mockRepository{deck: deck},
+			id:   deckID.String(),
+			want: Deck{ID: deckID, Remaining: 4, Counts: counts},
+		},
+		{
+			name: "empty deck",
+			r:    &mockRepository{deck: Deck{ID: deckID}},
+			id:   deckID.String(),
+			want: Deck{ID: deckID, Counts: Counts{Suits: map[string]int{}, Ranks: map[string]int{}}},
+		},
+		{
+			name:    "not found",
+			r:       &mockRepository{err: ErrNotFound},
+			id:      deckID.String(),
+			wantErr: ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewService(tt.r).List(tt.id, tt.showCards)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("List() error = %v, want %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("List() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_service_ListPile(t *testing.T) {
+	deckID, _ := uuid.Parse("a251071b-662f-44b6-ba11-e24863039c59")
+	cards := []Card{
+		{Code: "KD", Value: "KING", Suit: "DIAMONDS"},
+		{Code: "X1", Value: "JOKER", Suit: "NONE"},
+	}
+
+	tests := []struct {
+		name    string
+		r       Repository
+		id      string
+		want    Pile
+		wantErr error
+	}{
+		{
+			name: "describes french cards",
+			r:    &mockRepository{pile: cards},
+			id:   deckID.String(),
+			want: Pile{DeckID: deckID, Name: "dealer", Cards: []Card{
+				{Code: "KD", Value: "KING", Suit: "DIAMONDS", Rank: 13, Color: "RED"},
+				{Code: "X1", Value: "JOKER", Suit: "NONE"},
+			}},
+		},
+		{
+			name: "empty pile",
+			r:    &mockRepository{},
+			id:   deckID.String(),
+			want: Pile{DeckID: deckID, Name: "dealer", Cards: []Card{}},
+		},
+		{
+			name:    "invalid id",
+			r:       &mockRepository{},
+			id:      "deck",
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "not found",
+			r:       &mockRepository{err: ErrNotFound},
+			id:      deckID.String(),
+			wantErr: ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewService(tt.r).ListPile(tt.id, "dealer")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ListPile() error = %v, want %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListPile() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type mockRepository struct {
+	deck Deck
+	pile []Card
+	err  error
+}
+
+func (r *mockRepository) FindPile(uuid.UUID, string) ([]Card, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	return append([]Card(nil), r.pile...), nil
+}
+
+func (r *mockRepository) Find(uuid.UUID) (Deck, error) {
+	cards := append([]Card(nil), r.deck.Cards...)
+	deck := r.deck
+	deck.Cards = cards
+
+	return deck, r.err
+}
@@ -1,27 +1,58 @@
 package rest
 
 import (
+	"crypto/subtle"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/julienschmidt/httprouter"
 
+	"github.com/srgyrn/lucky-38/pkg/commitment"
 	"github.com/srgyrn/lucky-38/pkg/creating"
 	"github.com/srgyrn/lucky-38/pkg/drawing"
+	"github.com/srgyrn/lucky-38/pkg/history"
 	"github.com/srgyrn/lucky-38/pkg/listing"
 )
 
+// RequesterHeader names who asks for a draw, it is recorded in the deck's history
+const RequesterHeader = "X-Requester"
+
+// MaxRequesterLength is the number of characters of RequesterHeader kept in the deck's history
+const MaxRequesterLength = 255
+
+// CutCardHeader is set to "reached" on draws, deals and burns that reach the cut card of a deck, the deck is due to be
+// shuffled
+const CutCardHeader = "X-Cut-Card"
+
 // Handler creates a new router, registers routes and returns the created router.
-func Handler(cs creating.Service, ls listing.Service, ds drawing.Service) http.Handler {
+// Requests bearing adminToken in the Authorization header are authorised to see deck secrets, e.g. shuffle seeds,
+// peeked and burned cards, to move or return burned cards, to draw specific cards and to close decks.
+func Handler(cs creating.Service, ls listing.Service, ds drawing.Service, hs history.Service, ms commitment.Service, adminToken string) http.Handler {
 	router := httprouter.New()
 
 	router.GET("/health", health())
 	router.POST("/decks", createDeck(cs))
-	router.GET("/decks/:id", getDeck(ls))
-	router.PATCH("/decks/:id/draw/:amount", drawCards(ds))
+	router.POST("/definitions", createDefinition(cs))
+	router.GET("/decks/:id", getDeck(ls, adminToken))
+	router.PATCH("/decks/:id/draw/:amount", drawCards(ds, adminToken))
+	router.PATCH("/decks/:id/return", returnCards(ds, adminToken))
+	router.GET("/decks/:id/piles/:pile", getPile(ls, adminToken))
+	router.PATCH("/decks/:id/piles/:pile/draw/:amount", drawCards(ds, adminToken))
+	router.PATCH("/decks/:id/piles/:pile/move", moveCards(ds, adminToken))
+	router.PATCH("/decks/:id/piles/:pile/return", returnPile(ds, adminToken))
+	router.PATCH("/decks/:id/deal", dealCards(ds))
+	router.PATCH("/decks/:id/burn/:amount", burnCards(ds))
+	router.GET("/decks/:id/peek/:amount", peekCards(ds, adminToken))
+	router.PATCH("/decks/:id/cut", cutDeck(ds))
+	router.GET("/decks/:id/draws", getDraws(hs, adminToken))
+	router.PATCH("/decks/:id/close", closeDeck(ms, adminToken))
+	router.GET("/decks/:id/reveal", revealDeck(ms))
 	return router
 }
 
@@ -33,7 +64,8 @@ func health() func(w http.ResponseWriter, r *http.Request, _ httprouter.Params)
 	}
 }
 
-// createDeck returns a handler for POST /deck requests
+// createDeck returns a handler for POST /deck requests. Cards of a partial deck are either listed in the cards query
+// string or derived from the full deck with exclude, suits, ranks and min_rank.
 func createDeck(s creating.Service) func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 		decoder := json.NewDecoder(r.Body)
@@ -58,13 +90,26 @@ func createDeck(s creating.Service) func(w http.ResponseWriter, r *http.Request,
 			}
 		}
 
+		query := r.URL.Query()
+		newDeck.Filter = creating.Filter{
+			Exclude: list(query.Get("exclude")),
+			Suits:   list(query.Get("suits")),
+			Ranks:   list(query.Get("ranks")),
+		}
+		if minRank := query.Get("min_rank"); "" != minRank {
+			if newDeck.Filter.MinRank, err = strconv.Atoi(minRank); err != nil {
+				http.Error(w, "min_rank must be a number", http.StatusBadRequest)
+				return
+			}
+		}
+
 		newDeck, err = s.CreateDeck(newDeck)
-		if errors.As(err, &creating.ErrInvalidCard) || errors.Is(err, creating.ErrInvalidDeck) {
+		if errors.As(err, &creating.ErrInvalidCard) || errors.As(err, &creating.ErrDuplicateCards) || errors.Is(err, creating.ErrInvalidDeck) {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
 
-		if errors.Is(err, creating.ErrCreate) {
+		if errors.Is(err, creating.ErrCreate) || errors.Is(err, creating.ErrShuffle) {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
@@ -74,10 +119,46 @@ func createDeck(s creating.Service) func(w http.ResponseWriter, r *http.Request,
 	}
 }
 
-// getDeck returns a handler for GET /deck/<deck_id> requests
-func getDeck(s listing.Service) func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+// createDefinition returns a handler for POST /definitions requests, the response has the cards of the new deck type
+func createDefinition(s creating.Service) func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		var def creating.Definition
+		if err := json.NewDecoder(r.Body).Decode(&def); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		deckType, err := s.CreateDefinition(def)
+		if err != nil {
+			status := http.StatusInternalServerError
+			switch {
+			case errors.Is(err, creating.ErrInvalidDefinition):
+				status = http.StatusBadRequest
+			case errors.Is(err, creating.ErrDefinitionExists):
+				status = http.StatusConflict
+			}
+
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(deckType)
+	}
+}
+
+// getDeck returns a handler for GET /deck/<deck_id> requests. The seed is hidden from unauthorised callers, and
+// cards are only listed to authorised callers asking for them with show_cards=true.
+func getDeck(s listing.Service, adminToken string) func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
 	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
-		deck, err := s.List(params.ByName("id"))
+		showCards := "true" == r.URL.Query().Get("show_cards")
+		if showCards && !authorised(r, adminToken) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		deck, err := s.List(params.ByName("id"), showCards)
 		if err != nil {
 			status := http.StatusInternalServerError
 			if errors.Is(err, listing.ErrNotFound) {
@@ -88,14 +169,27 @@ func getDeck(s listing.Service) func(w http.ResponseWriter, r *http.Request, par
 			return
 		}
 
+		if !authorised(r, adminToken) {
+			deck.Seed = nil
+		}
+
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(deck)
 	}
 }
 
-// drawCards returns a handler for PUT /deck/<deck_id> requests
-func drawCards(s drawing.Service) func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+// drawCards returns a handler for PATCH /decks/<deck_id>/draw/<amount> requests, and for
+// PATCH /decks/<deck_id>/piles/<pile>/draw/<amount> requests drawing into a pile. The mode query string selects
+// drawing.Mode, the cards query string lists codes to draw in drawing.ModeSpecific, which only authorised callers
+// can use. Once the cut card of the deck is reached, responses have the CutCardHeader.
+func drawCards(s drawing.Service, adminToken string) func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
 	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		mode := drawing.Mode(r.URL.Query().Get("mode"))
+		if drawing.ModeSpecific == mode && !authorised(r, adminToken) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
 		deckID := params.ByName("id")
 		amount := params.ByName("amount")
 
@@ -110,20 +204,473 @@ func drawCards(s drawing.Service) func(w http.ResponseWriter, r *http.Request, p
 			return
 		}
 
-		cards, err := s.Draw(deckID, n)
+		opts := drawing.Options{
+			Mode:  mode,
+			Codes: list(r.URL.Query().Get("cards")),
+			Pile:  params.ByName("pile"),
+		}
+
+		cards, reached, err := s.DrawWith(deckID, n, opts, requester(r))
 		if err != nil {
 			if errors.Is(err, drawing.ErrNotFound) {
 				http.Error(w, err.Error(), http.StatusNotFound)
 				return
 			}
 
-			if errors.Is(err, drawing.ErrInsufficientRemainingCard) {
+			if errors.Is(err, drawing.ErrInsufficientRemainingCard) || errors.Is(err, drawing.ErrInvalidAmount) || errors.Is(err, drawing.ErrInvalidPile) ||
+				errors.Is(err, drawing.ErrInvalidMode) || errors.Is(err, drawing.ErrCardNotAvailable) {
 				http.Error(w, err.Error(), http.StatusBadRequest)
 				return
 			}
+
+			if errors.Is(err, drawing.ErrDeckClosed) {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if reached {
+			w.Header().Set(CutCardHeader, "reached")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(cards)
+	}
+}
+
+// returnCards returns a handler for PATCH /decks/<deck_id>/return requests. Returned cards of drawing.BurnPile are
+// left out of responses to unauthorised callers.
+func returnCards(s drawing.Service, adminToken string) func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		var body struct {
+			Cards   []string `json:"cards"`
+			Shuffle bool     `json:"shuffle"`
+		}
+
+		// body is optional, every drawn card is returned without it
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil && io.EOF != err {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		cards, err := s.Return(params.ByName("id"), body.Cards, body.Shuffle, requester(r))
+		if err != nil {
+			status := http.StatusInternalServerError
+			switch {
+			case errors.Is(err, drawing.ErrNotFound):
+				status = http.StatusNotFound
+			case errors.Is(err, drawing.ErrCardNotDrawn):
+				status = http.StatusBadRequest
+			case errors.Is(err, drawing.ErrDeckClosed):
+				status = http.StatusConflict
+			}
+
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		if !authorised(r, adminToken) {
+			shown := make([]drawing.Card, 0, len(cards))
+			for _, c := range cards {
+				if drawing.BurnPile != c.Pile {
+					shown = append(shown, c)
+				}
+			}
+			cards = shown
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(cards)
+	}
+}
+
+// burnCards returns a handler for PATCH /decks/<deck_id>/burn/<amount> requests, burned cards are not shown. Once the
+// cut card of the deck is reached, responses have the CutCardHeader.
+func burnCards(s drawing.Service) func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		n, err := strconv.Atoi(params.ByName("amount"))
+		if err != nil {
+			http.Error(w, "amount must be a number", http.StatusBadRequest)
+			return
+		}
+
+		_, reached, err := s.Burn(params.ByName("id"), n, requester(r))
+		if err != nil {
+			http.Error(w, err.Error(), drawStatus(err))
+			return
+		}
+
+		if reached {
+			w.Header().Set(CutCardHeader, "reached")
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// peekCards returns a handler for GET /decks/<deck_id>/peek/<amount> requests, only authorised callers can peek
+func peekCards(s drawing.Service, adminToken string) func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		if !authorised(r, adminToken) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		n, err := strconv.Atoi(params.ByName("amount"))
+		if err != nil {
+			http.Error(w, "amount must be a number", http.StatusBadRequest)
+			return
+		}
+
+		cards, err := s.Peek(params.ByName("id"), n, requester(r))
+		if err != nil {
+			http.Error(w, err.Error(), drawStatus(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(cards)
+	}
+}
+
+// cutDeck returns a handler for PATCH /decks/<deck_id>/cut requests, the deck is cut at a random position unless the
+// body has one
+func cutDeck(s drawing.Service) func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		var body struct {
+			Position int `json:"position"`
+		}
+
+		// body is optional, the deck is cut at random without it
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil && io.EOF != err {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		position, err := s.Cut(params.ByName("id"), body.Position, requester(r))
+		if err != nil {
+			status := drawStatus(err)
+			if errors.Is(err, drawing.ErrInvalidCut) {
+				status = http.StatusBadRequest
+			}
+
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		body.Position = position
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(body)
+	}
+}
+
+// drawStatus returns the response status of an error of drawing cards from the top of a deck
+func drawStatus(err error) int {
+	switch {
+	case errors.Is(err, drawing.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, drawing.ErrInsufficientRemainingCard) || errors.Is(err, drawing.ErrInvalidAmount):
+		return http.StatusBadRequest
+	case errors.Is(err, drawing.ErrDeckClosed):
+		return http.StatusConflict
+	}
+
+	return http.StatusInternalServerError
+}
+
+// dealCards returns a handler for PATCH /decks/<deck_id>/deal requests. Cards are dealt to the named seats, or to
+// seats player1 to playerN for the given number of players. Once the cut card of the deck is reached, responses have
+// the CutCardHeader.
+func dealCards(s drawing.Service) func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		var body struct {
+			Players int      `json:"players"`
+			Seats   []string `json:"seats"`
+			Cards   int      `json:"cards"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		seats := body.Seats
+		if 0 == len(seats) {
+			if 1 > body.Players || drawing.MaxSeats < body.Players {
+				http.Error(w, fmt.Sprintf("players must be 1 to %d unless seats are given", drawing.MaxSeats), http.StatusBadRequest)
+				return
+			}
+
+			seats = drawing.Seats(body.Players)
+		}
+
+		hands, reached, err := s.Deal(params.ByName("id"), seats, body.Cards, requester(r))
+		if err != nil {
+			status := http.StatusInternalServerError
+			switch {
+			case errors.Is(err, drawing.ErrNotFound):
+				status = http.StatusNotFound
+			case errors.Is(err, drawing.ErrInsufficientRemainingCard) || errors.Is(err, drawing.ErrInvalidAmount) || errors.Is(err, drawing.ErrInvalidPile):
+				status = http.StatusBadRequest
+			case errors.Is(err, drawing.ErrDeckClosed):
+				status = http.StatusConflict
+			}
+
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		if reached {
+			w.Header().Set(CutCardHeader, "reached")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(hands)
+	}
+}
+
+// getPile returns a handler for GET /decks/<deck_id>/piles/<pile> requests, only authorised callers can list
+// drawing.BurnPile
+func getPile(s listing.Service, adminToken string) func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		if drawing.BurnPile == params.ByName("pile") && !authorised(r, adminToken) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		pile, err := s.ListPile(params.ByName("id"), params.ByName("pile"))
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, listing.ErrNotFound) {
+				status = http.StatusNotFound
+			}
+
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(pile)
+	}
+}
+
+// moveCards returns a handler for PATCH /decks/<deck_id>/piles/<pile>/move requests, only authorised callers can
+// move cards out of drawing.BurnPile
+func moveCards(s drawing.Service, adminToken string) func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		if drawing.BurnPile == params.ByName("pile") && !authorised(r, adminToken) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		var body struct {
+			To    string   `json:"to"`
+			Cards []string `json:"cards"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		cards, err := s.Move(params.ByName("id"), params.ByName("pile"), body.To, body.Cards, requester(r))
+		if err != nil {
+			status := http.StatusInternalServerError
+			switch {
+			case errors.Is(err, drawing.ErrNotFound):
+				status = http.StatusNotFound
+			case errors.Is(err, drawing.ErrInvalidPile) || errors.Is(err, drawing.ErrCardNotInPile):
+				status = http.StatusBadRequest
+			case errors.Is(err, drawing.ErrDeckClosed):
+				status = http.StatusConflict
+			}
+
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(cards)
+	}
+}
+
+// returnPile returns a handler for PATCH /decks/<deck_id>/piles/<pile>/return requests, only authorised callers can
+// return drawing.BurnPile
+func returnPile(s drawing.Service, adminToken string) func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		if drawing.BurnPile == params.ByName("pile") && !authorised(r, adminToken) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		var body struct {
+			Shuffle bool `json:"shuffle"`
+		}
+
+		// body is optional, returned cards go to the bottom of the deck without it
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil && io.EOF != err {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		cards, err := s.ReturnPile(params.ByName("id"), params.ByName("pile"), body.Shuffle, requester(r))
+		if err != nil {
+			status := http.StatusInternalServerError
+			switch {
+			case errors.Is(err, drawing.ErrNotFound):
+				status = http.StatusNotFound
+			case errors.Is(err, drawing.ErrInvalidPile):
+				status = http.StatusBadRequest
+			case errors.Is(err, drawing.ErrDeckClosed):
+				status = http.StatusConflict
+			}
+
+			http.Error(w, err.Error(), status)
+			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(cards)
 	}
 }
+
+// getDraws returns a handler for GET /decks/<deck_id>/draws requests. Cards of peeks and burns, and burned cards
+// moved or returned afterwards, are hidden from unauthorised callers.
+func getDraws(s history.Service, adminToken string) func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		draws, err := s.Draws(params.ByName("id"))
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, history.ErrNotFound) {
+				status = http.StatusNotFound
+			}
+
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		if !authorised(r, adminToken) {
+			hideSecrets(draws)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(draws)
+	}
+}
+
+// closeDeck returns a handler for PATCH /decks/<deck_id>/close requests, only authorised callers can close a deck
+func closeDeck(s commitment.Service, adminToken string) func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		if !authorised(r, adminToken) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		if err := s.Close(params.ByName("id")); err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, commitment.ErrNotFound) {
+				status = http.StatusNotFound
+			}
+
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// revealDeck returns a handler for GET /decks/<deck_id>/reveal requests
+func revealDeck(s commitment.Service) func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		reveal, err := s.Reveal(params.ByName("id"))
+		if err != nil {
+			status := http.StatusInternalServerError
+			switch {
+			case errors.Is(err, commitment.ErrNotFound) || errors.Is(err, commitment.ErrNoCommitment):
+				status = http.StatusNotFound
+			case errors.Is(err, commitment.ErrNotRevealable):
+				status = http.StatusConflict
+			}
+
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(reveal)
+	}
+}
+
+// list splits a comma separated query value, leaving out empty items
+func list(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); "" != item {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// requester returns who sent the request: RequesterHeader if set, cut to MaxRequesterLength characters, remote
+// address otherwise
+func requester(r *http.Request) string {
+	if name := strings.TrimSpace(r.Header.Get(RequesterHeader)); "" != name {
+		if runes := []rune(name); MaxRequesterLength < len(runes) {
+			return strings.TrimSpace(string(runes[:MaxRequesterLength]))
+		}
+
+		return name
+	}
+
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+
+	return r.RemoteAddr
+}
+
+// hideSecrets removes cards of peeks and burns from draws, along with burned cards from the moves and returns that
+// follow until they are returned to the deck.
+func hideSecrets(draws []history.Draw) {
+	burned := make(map[int]bool)
+	for i, d := range draws {
+		switch d.Action {
+		case history.ActionPeek:
+			draws[i].Cards = []history.Card{}
+		case history.ActionBurn:
+			for _, c := range d.Cards {
+				burned[c.ID] = true
+			}
+			draws[i].Cards = []history.Card{}
+		case history.ActionMove, history.ActionReturn:
+			shown := make([]history.Card, 0, len(d.Cards))
+			for _, c := range d.Cards {
+				if !burned[c.ID] {
+					shown = append(shown, c)
+				} else if history.ActionReturn == d.Action {
+					delete(burned, c.ID)
+				}
+			}
+			draws[i].Cards = shown
+		}
+	}
+}
+
+// authorised reports if the request bears adminToken as "Authorization: Bearer <token>".
+// Nobody is authorised when adminToken is empty.
+func authorised(r *http.Request, adminToken string) bool {
+	header := r.Header.Get("Authorization")
+	if "" == adminToken || !strings.HasPrefix(header, "Bearer ") {
+		return false
+	}
+
+	token := strings.TrimPrefix(header, "Bearer ")
+
+	return 1 == subtle.ConstantTimeCompare([]byte(token), []byte(adminToken))
+}
@@ -9,14 +9,19 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/julienschmidt/httprouter"
 
+	"github.com/srgyrn/lucky-38/pkg/commitment"
 	"github.com/srgyrn/lucky-38/pkg/creating"
 	"github.com/srgyrn/lucky-38/pkg/drawing"
+	"github.com/srgyrn/lucky-38/pkg/history"
 	"github.com/srgyrn/lucky-38/pkg/listing"
+	"github.com/srgyrn/lucky-38/pkg/storage/memory"
 )
 
 func Test_createDeck(t *testing.T) {
@@ -67,6 +72,48 @@ func Test_createDeck(t *testing.T) {
 			want:       creating.Deck{},
 			wantStatus: http.StatusBadRequest,
 		},
+		{
+			name: "duplicate cards",
+			reqParams: requestParams{
+				body:  `{"shuffled": false}`,
+				query: map[string]string{"cards": "AS,AS"},
+			},
+			service:    &mockCreateService{err: &creating.DuplicateCardsErr{Codes: []string{"AS"}}},
+			want:       creating.Deck{},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "filtered",
+			reqParams: requestParams{
+				body:  `{"shuffled": false}`,
+				query: map[string]string{"suits": "H, S", "ranks": "A,K", "exclude": "AS", "min_rank": "9"},
+			},
+			service: &mockCreateService{
+				out: creating.Deck{ID: deckID, Remaining: 3},
+			},
+			want:       creating.Deck{ID: deckID, Remaining: 3},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "invalid min rank",
+			reqParams: requestParams{
+				body:  `{"shuffled": false}`,
+				query: map[string]string{"min_rank": "nine"},
+			},
+			service:    &mockCreateService{},
+			want:       creating.Deck{},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "unknown suit",
+			reqParams: requestParams{
+				body:  `{"shuffled": false}`,
+				query: map[string]string{"suits": "Z"},
+			},
+			service:    &mockCreateService{err: creating.ErrInvalidDeck},
+			want:       creating.Deck{},
+			wantStatus: http.StatusBadRequest,
+		},
 		{
 			name: "handles error from DB",
 			reqParams: requestParams{
@@ -111,30 +158,124 @@ func Test_createDeck(t *testing.T) {
 	}
 }
 
+func Test_list(t *testing.T) {
+	tests := map[string][]string{
+		"":          nil,
+		"H":         {"H"},
+		" H, S ,,":  {"H", "S"},
+		"2S,3S,10S": {"2S", "3S", "10S"},
+	}
+	for value, want := range tests {
+		if got := list(value); !reflect.DeepEqual(got, want) {
+			t.Errorf("list(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func Test_requester(t *testing.T) {
+	tests := map[string]string{
+		"":                              "192.0.2.1",
+		" dealer ":                      "dealer",
+		strings.Repeat("♠", 300):        strings.Repeat("♠", MaxRequesterLength),
+		strings.Repeat("a", 254) + " b": strings.Repeat("a", 254),
+	}
+	for header, want := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(RequesterHeader, header)
+		if got := requester(req); want != got {
+			t.Errorf("requester(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
 type mockCreateService struct {
-	out creating.Deck
-	err error
+	out      creating.Deck
+	deckType creating.DeckType
+	err      error
 }
 
 func (ms *mockCreateService) CreateDeck(deck creating.Deck) (creating.Deck, error) {
 	return ms.out, ms.err
 }
 
+func (ms *mockCreateService) CreateDefinition(def creating.Definition) (creating.DeckType, error) {
+	return ms.deckType, ms.err
+}
+
+func Test_createDefinition(t *testing.T) {
+	deckType := creating.DeckType{Name: "colors", Cards: []creating.Card{{Code: "1R", Value: "ONE", Suit: "RED"}}}
+	body := `{"name": "colors", "suits": [{"code": "R", "name": "RED"}], "ranks": [{"code": "1", "name": "ONE"}]}`
+
+	tests := []struct {
+		name       string
+		body       string
+		service    creating.Service
+		wantStatus int
+	}{
+		{name: "valid", body: body, service: &mockCreateService{deckType: deckType}, wantStatus: http.StatusCreated},
+		{name: "malformed body", body: `{"name":`, service: &mockCreateService{}, wantStatus: http.StatusBadRequest},
+		{name: "invalid", body: body, service: &mockCreateService{err: creating.ErrInvalidDefinition}, wantStatus: http.StatusBadRequest},
+		{name: "exists", body: body, service: &mockCreateService{err: creating.ErrDefinitionExists}, wantStatus: http.StatusConflict},
+		{name: "db fail", body: body, service: &mockCreateService{err: creating.ErrCreate}, wantStatus: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := httprouter.New()
+			router.POST("/definitions", createDefinition(tt.service))
+
+			req := httptest.NewRequest(http.MethodPost, "/definitions", bytes.NewBufferString(tt.body))
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("createDefinition() status %d, want %d", rr.Code, tt.wantStatus)
+				return
+			}
+
+			var got creating.DeckType
+			json.Unmarshal(rr.Body.Bytes(), &got)
+
+			if http.StatusCreated == tt.wantStatus && !reflect.DeepEqual(got, deckType) {
+				t.Errorf("createDefinition() got %v, want %v", got, deckType)
+			}
+		})
+	}
+}
+
 type mockListService struct {
-	out listing.Deck
-	err error
+	out  listing.Deck
+	pile listing.Pile
+	err  error
+}
+
+func (mls *mockListService) List(ID string, showCards bool) (listing.Deck, error) {
+	deck := mls.out
+	if !showCards {
+		deck.Cards = nil
+	}
+
+	return deck, mls.err
 }
 
-func (mls *mockListService) List(ID string) (listing.Deck, error) {
-	return mls.out, mls.err
+func (mls *mockListService) ListPile(ID, name string) (listing.Pile, error) {
+	return mls.pile, mls.err
 }
 
 func Test_getDeck(t *testing.T) {
 	deckID, _ := uuid.Parse("a251071b-662f-44b6-ba11-e24863039c59")
+	seed := int64(42)
 	type args struct {
 		service listing.Service
 		deckID  string
+		token   string
+		query   string
+	}
+	cards := []listing.Card{
+		{ID: 1, Code: "AS", Value: "ACE", Suit: "SPADES"},
+		{ID: 2, Code: "2S", Value: "2", Suit: "SPADES"},
 	}
+	counts := listing.Counts{Suits: map[string]int{"SPADES": 2}, Ranks: map[string]int{"ACE": 1, "2": 1}}
 	tests := []struct {
 		name       string
 		args       args
@@ -184,6 +325,8 @@ func Test_getDeck(t *testing.T) {
 					},
 				},
 				deckID: "a251071b-662f-44b6-ba11-e24863039c59",
+				token:  "secret",
+				query:  "?show_cards=true",
 			},
 			want: listing.Deck{
 				ID:        deckID,
@@ -204,13 +347,56 @@ func Test_getDeck(t *testing.T) {
 			},
 			wantStatus: http.StatusOK,
 		},
+		{
+			name: "hides cards by default",
+			args: args{
+				service: &mockListService{out: listing.Deck{ID: deckID, Remaining: 2, Counts: counts, Cards: cards}},
+				deckID:  "a251071b-662f-44b6-ba11-e24863039c59",
+				token:   "secret",
+			},
+			want:       listing.Deck{ID: deckID, Remaining: 2, Counts: counts},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "cards need authorisation",
+			args: args{
+				service: &mockListService{out: listing.Deck{ID: deckID, Remaining: 2, Counts: counts, Cards: cards}},
+				deckID:  "a251071b-662f-44b6-ba11-e24863039c59",
+				token:   "wrong",
+				query:   "?show_cards=true",
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "hides seed from unauthorised",
+			args: args{
+				service: &mockListService{out: listing.Deck{ID: deckID, Shuffled: true, Seed: &seed}},
+				deckID:  "a251071b-662f-44b6-ba11-e24863039c59",
+				token:   "wrong",
+			},
+			want:       listing.Deck{ID: deckID, Shuffled: true},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "shows seed to authorised",
+			args: args{
+				service: &mockListService{out: listing.Deck{ID: deckID, Shuffled: true, Seed: &seed}},
+				deckID:  "a251071b-662f-44b6-ba11-e24863039c59",
+				token:   "secret",
+			},
+			want:       listing.Deck{ID: deckID, Shuffled: true, Seed: &seed},
+			wantStatus: http.StatusOK,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			router := httprouter.New()
-			router.GET("/deck/:id", getDeck(tt.args.service))
+			router.GET("/deck/:id", getDeck(tt.args.service, "secret"))
 
-			req := httptest.NewRequest(http.MethodGet, "/deck/"+tt.args.deckID, nil)
+			req := httptest.NewRequest(http.MethodGet, "/deck/"+tt.args.deckID+tt.args.query, nil)
+			if "" != tt.args.token {
+				req.Header.Set("Authorization", "Bearer "+tt.args.token)
+			}
 			rr := httptest.NewRecorder()
 
 			router.ServeHTTP(rr, req)
@@ -264,6 +450,30 @@ func Test_drawCards(t *testing.T) {
 			wantStatus:   http.StatusBadRequest,
 			wantResponse: []drawing.Card{},
 		},
+		{
+			name: "handles closed deck",
+			args: args{
+				amount: 2,
+				s: &mockDrawingService{
+					out: []drawing.Card{},
+					err: drawing.ErrDeckClosed,
+				},
+			},
+			wantStatus:   http.StatusConflict,
+			wantResponse: []drawing.Card{},
+		},
+		{
+			name: "handles service error",
+			args: args{
+				amount: 2,
+				s: &mockDrawingService{
+					out: []drawing.Card{},
+					err: errors.New("test error"),
+				},
+			},
+			wantStatus:   http.StatusInternalServerError,
+			wantResponse: []drawing.Card{},
+		},
 		{
 			name: "valid",
 			args: args{
@@ -292,10 +502,11 @@ func Test_drawCards(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			router := httprouter.New()
-			router.PATCH("/deck/:id/draw/:amount", drawCards(tt.args.s))
+			router.PATCH("/deck/:id/draw/:amount", drawCards(tt.args.s, "secret"))
 
 			uri := fmt.Sprintf("/deck/test-test-test/draw/%d", tt.args.amount)
 			req := httptest.NewRequest(http.MethodPatch, uri, nil)
+			req.Header.Set(RequesterHeader, "dealer")
 			rr := httptest.NewRecorder()
 
 			router.ServeHTTP(rr, req)
@@ -305,6 +516,10 @@ func Test_drawCards(t *testing.T) {
 				return
 			}
 
+			if got := tt.args.s.(*mockDrawingService).requester; "dealer" != got {
+				t.Errorf("drawCards() requester %q, want %q", got, "dealer")
+			}
+
 			var got []drawing.Card
 			json.Unmarshal(rr.Body.Bytes(), &got)
 			if http.StatusOK == tt.wantStatus && !reflect.DeepEqual(got, tt.wantResponse) {
@@ -315,10 +530,1046 @@ func Test_drawCards(t *testing.T) {
 }
 
 type mockDrawingService struct {
-	out []drawing.Card
+	out       []drawing.Card
+	err       error
+	requester string
+	codes     []string
+	shuffle   bool
+	pile      string
+	to        string
+	seats     []string
+	hands     []drawing.Hand
+	opts      drawing.Options
+	burned    int
+	reached   bool
+	position  int
+}
+
+func (ms *mockDrawingService) Draw(deckID string, n int, requester string) ([]drawing.Card, bool, error) {
+	ms.requester = requester
+	return ms.out, ms.reached, ms.err
+}
+
+func (ms *mockDrawingService) DrawInto(deckID, pile string, n int, requester string) ([]drawing.Card, bool, error) {
+	ms.requester, ms.pile = requester, pile
+	return ms.out, ms.reached, ms.err
+}
+
+func (ms *mockDrawingService) DrawWith(deckID string, n int, opts drawing.Options, requester string) ([]drawing.Card, bool, error) {
+	ms.requester, ms.pile, ms.opts = requester, opts.Pile, opts
+	return ms.out, ms.reached, ms.err
+}
+
+func (ms *mockDrawingService) Cut(deckID string, position int, requester string) (int, error) {
+	ms.requester = requester
+	if ms.err != nil {
+		return 0, ms.err
+	}
+
+	// a random cut is made at position 1
+	ms.position = position
+	if 0 == position {
+		ms.position = 1
+	}
+
+	return ms.position, nil
+}
+
+func (ms *mockDrawingService) Burn(deckID string, n int, requester string) (int, bool, error) {
+	ms.requester = requester
+	if ms.err != nil {
+		return 0, false, ms.err
+	}
+
+	ms.burned = n
+	return n, ms.reached, nil
+}
+
+func (ms *mockDrawingService) Peek(deckID string, n int, requester string) ([]drawing.Card, error) {
+	ms.requester = requester
+	return ms.out, ms.err
+}
+
+func (ms *mockDrawingService) Return(deckID string, codes []string, shuffle bool, requester string) ([]drawing.Card, error) {
+	ms.requester = requester
+	ms.codes, ms.shuffle = codes, shuffle
+	return ms.out, ms.err
+}
+
+func (ms *mockDrawingService) ReturnPile(deckID, pile string, shuffle bool, requester string) ([]drawing.Card, error) {
+	ms.requester, ms.pile, ms.shuffle = requester, pile, shuffle
+	return ms.out, ms.err
+}
+
+func (ms *mockDrawingService) Deal(deckID string, seats []string, n int, requester string) ([]drawing.Hand, bool, error) {
+	ms.requester, ms.seats = requester, seats
+	return ms.hands, ms.reached, ms.err
+}
+
+func (ms *mockDrawingService) Move(deckID, from, to string, codes []string, requester string) ([]drawing.Card, error) {
+	ms.requester, ms.pile, ms.to, ms.codes = requester, from, to, codes
+	return ms.out, ms.err
+}
+
+type mockHistoryService struct {
+	out []history.Draw
 	err error
 }
 
-func (ms *mockDrawingService) Draw(deckID string, n int) ([]drawing.Card, error) {
+func (ms *mockHistoryService) Draws(deckID string) ([]history.Draw, error) {
+	return ms.out, ms.err
+}
+
+func Test_getDraws(t *testing.T) {
+	deckID, _ := uuid.Parse("a251071b-662f-44b6-ba11-e24863039c59")
+	drawID, _ := uuid.Parse("69077400-88cd-11eb-8dcd-0242ac130003")
+	draws := []history.Draw{
+		{
+			ID:        drawID,
+			DeckID:    deckID,
+			Number:    1,
+			Requester: "dealer",
+			DrawnAt:   time.Date(2021, 3, 20, 10, 0, 0, 0, time.UTC),
+			Cards: []history.Card{
+				{Code: "AS", Value: "ACE", Suit: "SPADES"},
+			},
+		},
+	}
+	secrets := func() []history.Draw {
+		return []history.Draw{
+			{DeckID: deckID, Number: 1, Action: history.ActionDraw, Cards: []history.Card{{Code: "AS"}}},
+			{DeckID: deckID, Number: 2, Action: history.ActionPeek, Cards: []history.Card{{Code: "KH"}}},
+			{DeckID: deckID, Number: 3, Action: history.ActionBurn, Pile: "burn", Cards: []history.Card{{Code: "KH"}}},
+		}
+	}
+	redacted := secrets()
+	redacted[1].Cards, redacted[2].Cards = []history.Card{}, []history.Card{}
+
+	tests := []struct {
+		name       string
+		service    history.Service
+		token      string
+		want       []history.Draw
+		wantStatus int
+	}{
+		{
+			name:       "handles not found",
+			service:    &mockHistoryService{err: history.ErrNotFound},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "handles db error",
+			service:    &mockHistoryService{err: errors.New("test error")},
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "valid",
+			service:    &mockHistoryService{out: draws},
+			want:       draws,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "hides peeked and burned cards",
+			service:    &mockHistoryService{out: secrets()},
+			want:       redacted,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "hides peeked and burned cards/wrong token",
+			service:    &mockHistoryService{out: secrets()},
+			token:      "wrong",
+			want:       redacted,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "shows peeked and burned cards to authorised",
+			service:    &mockHistoryService{out: secrets()},
+			token:      "secret",
+			want:       secrets(),
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := httprouter.New()
+			router.GET("/decks/:id/draws", getDraws(tt.service, "secret"))
+
+			req := httptest.NewRequest(http.MethodGet, "/decks/"+deckID.String()+"/draws", nil)
+			if "" != tt.token {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("getDraws() status code %d, want %d", rr.Code, tt.wantStatus)
+				return
+			}
+
+			var got []history.Draw
+			json.Unmarshal(rr.Body.Bytes(), &got)
+			if http.StatusOK == tt.wantStatus && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getDraws() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_returnCards(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		s           *mockDrawingService
+		wantStatus  int
+		wantCodes   []string
+		wantShuffle bool
+	}{
+		{
+			name:       "handles not found",
+			s:          &mockDrawingService{err: drawing.ErrNotFound},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "handles card not drawn",
+			body:       `{"cards": ["AS"]}`,
+			s:          &mockDrawingService{err: drawing.ErrCardNotDrawn},
+			wantStatus: http.StatusBadRequest,
+			wantCodes:  []string{"AS"},
+		},
+		{
+			name:       "handles closed deck",
+			s:          &mockDrawingService{err: drawing.ErrDeckClosed},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "handles invalid body",
+			body:       `{"cards": "AS"}`,
+			s:          &mockDrawingService{},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "all cards without body",
+			s:          &mockDrawingService{out: []drawing.Card{{ID: 1, Value: "ACE", Suit: "SPADES", Code: "AS"}}},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:        "specific cards shuffled",
+			body:        `{"cards": ["AS", "KD"], "shuffle": true}`,
+			s:           &mockDrawingService{out: []drawing.Card{{ID: 1, Value: "ACE", Suit: "SPADES", Code: "AS"}}},
+			wantStatus:  http.StatusOK,
+			wantCodes:   []string{"AS", "KD"},
+			wantShuffle: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := httprouter.New()
+			router.PATCH("/decks/:id/return", returnCards(tt.s, "secret"))
+
+			req := httptest.NewRequest(http.MethodPatch, "/decks/test-test-test/return", bytes.NewBufferString(tt.body))
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("returnCards() status code %d, want %d", rr.Code, tt.wantStatus)
+				return
+			}
+
+			if http.StatusBadRequest != rr.Code && (!reflect.DeepEqual(tt.s.codes, tt.wantCodes) || tt.s.shuffle != tt.wantShuffle) {
+				t.Errorf("returnCards() passed codes %v shuffle %v, want %v %v", tt.s.codes, tt.s.shuffle, tt.wantCodes, tt.wantShuffle)
+			}
+
+			var got []drawing.Card
+			json.Unmarshal(rr.Body.Bytes(), &got)
+			if http.StatusOK == tt.wantStatus && !reflect.DeepEqual(got, []drawing.Card{{Value: "ACE", Suit: "SPADES", Code: "AS"}}) {
+				t.Errorf("returnCards() = %v", got)
+			}
+		})
+	}
+}
+
+type mockCommitmentService struct {
+	out    commitment.Reveal
+	err    error
+	closed string
+}
+
+func (ms *mockCommitmentService) Reveal(deckID string) (commitment.Reveal, error) {
 	return ms.out, ms.err
 }
+
+func (ms *mockCommitmentService) Close(deckID string) error {
+	ms.closed = deckID
+	return ms.err
+}
+
+func Test_closeDeck(t *testing.T) {
+	deckID := "a251071b-662f-44b6-ba11-e24863039c59"
+	tests := []struct {
+		name       string
+		token      string
+		s          *mockCommitmentService
+		wantStatus int
+		wantClosed string
+	}{
+		{
+			name:       "handles unauthorised",
+			token:      "wrong",
+			s:          &mockCommitmentService{},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "handles not found",
+			token:      "secret",
+			s:          &mockCommitmentService{err: commitment.ErrNotFound},
+			wantStatus: http.StatusNotFound,
+			wantClosed: deckID,
+		},
+		{
+			name:       "valid",
+			token:      "secret",
+			s:          &mockCommitmentService{},
+			wantStatus: http.StatusNoContent,
+			wantClosed: deckID,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := httprouter.New()
+			router.PATCH("/decks/:id/close", closeDeck(tt.s, "secret"))
+
+			req := httptest.NewRequest(http.MethodPatch, "/decks/"+deckID+"/close", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("closeDeck() status code %d, want %d", rr.Code, tt.wantStatus)
+			}
+
+			if tt.wantClosed != tt.s.closed {
+				t.Errorf("closeDeck() closed %q, want %q", tt.s.closed, tt.wantClosed)
+			}
+		})
+	}
+}
+
+func Test_revealDeck(t *testing.T) {
+	deckID, _ := uuid.Parse("a251071b-662f-44b6-ba11-e24863039c59")
+	reveal := commitment.Reveal{
+		DeckID:     deckID,
+		Commitment: commitment.Compute("salt", []string{"AS"}),
+		Salt:       "salt",
+		Closed:     true,
+		Remaining:  1,
+		Cards:      []commitment.Card{{Code: "AS", Value: "ACE", Suit: "SPADES"}},
+	}
+
+	tests := []struct {
+		name       string
+		service    commitment.Service
+		want       commitment.Reveal
+		wantStatus int
+	}{
+		{
+			name:       "handles not found",
+			service:    &mockCommitmentService{err: commitment.ErrNotFound},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "handles no commitment",
+			service:    &mockCommitmentService{err: commitment.ErrNoCommitment},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "handles deck in play",
+			service:    &mockCommitmentService{err: commitment.ErrNotRevealable},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "valid",
+			service:    &mockCommitmentService{out: reveal},
+			want:       reveal,
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := httprouter.New()
+			router.GET("/decks/:id/reveal", revealDeck(tt.service))
+
+			req := httptest.NewRequest(http.MethodGet, "/decks/"+deckID.String()+"/reveal", nil)
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("revealDeck() status code %d, want %d", rr.Code, tt.wantStatus)
+				return
+			}
+
+			var got commitment.Reveal
+			json.Unmarshal(rr.Body.Bytes(), &got)
+			if http.StatusOK == tt.wantStatus && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("revealDeck() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_drawCards_pile(t *testing.T) {
+	tests := []struct {
+		name       string
+		s          *mockDrawingService
+		wantStatus int
+	}{
+		{name: "valid", s: &mockDrawingService{out: []drawing.Card{{Code: "AS", Pile: "player1"}}}, wantStatus: http.StatusOK},
+		{name: "handles invalid pile", s: &mockDrawingService{err: drawing.ErrInvalidPile}, wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := httprouter.New()
+			router.PATCH("/decks/:id/piles/:pile/draw/:amount", drawCards(tt.s, "secret"))
+
+			req := httptest.NewRequest(http.MethodPatch, "/decks/test-test-test/piles/player1/draw/1", nil)
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code || "player1" != tt.s.pile {
+				t.Errorf("drawCards() status code %d into %q, want %d into player1", rr.Code, tt.s.pile, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_getPile(t *testing.T) {
+	deckID, _ := uuid.Parse("a251071b-662f-44b6-ba11-e24863039c59")
+	pile := listing.Pile{DeckID: deckID, Name: "dealer", Cards: []listing.Card{{Code: "KH", Value: "KING", Suit: "HEARTS"}}}
+
+	tests := []struct {
+		name       string
+		s          listing.Service
+		pile       string
+		token      string
+		wantStatus int
+	}{
+		{name: "valid", s: &mockListService{pile: pile}, pile: "dealer", wantStatus: http.StatusOK},
+		{name: "handles not found", s: &mockListService{err: listing.ErrNotFound}, pile: "dealer", wantStatus: http.StatusNotFound},
+		{name: "handles service error", s: &mockListService{err: errors.New("test error")}, pile: "dealer", wantStatus: http.StatusInternalServerError},
+		{name: "burn", s: &mockListService{pile: pile}, pile: drawing.BurnPile, token: "secret", wantStatus: http.StatusOK},
+		{name: "burn/handles unauthorised", s: &mockListService{pile: pile}, pile: drawing.BurnPile, wantStatus: http.StatusUnauthorized},
+		{name: "burn/handles wrong token", s: &mockListService{pile: pile}, pile: drawing.BurnPile, token: "wrong", wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := httprouter.New()
+			router.GET("/decks/:id/piles/:pile", getPile(tt.s, "secret"))
+
+			req := httptest.NewRequest(http.MethodGet, "/decks/"+deckID.String()+"/piles/"+tt.pile, nil)
+			if "" != tt.token {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("getPile() status code %d, want %d", rr.Code, tt.wantStatus)
+				return
+			}
+
+			var got listing.Pile
+			json.Unmarshal(rr.Body.Bytes(), &got)
+			if http.StatusOK == tt.wantStatus && !reflect.DeepEqual(got, pile) {
+				t.Errorf("getPile() = %v, want %v", got, pile)
+			}
+		})
+	}
+}
+
+func Test_moveCards(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		s          *mockDrawingService
+		wantStatus int
+		wantCodes  []string
+	}{
+		{
+			name:       "valid",
+			body:       `{"to": "discard", "cards": ["AS"]}`,
+			s:          &mockDrawingService{out: []drawing.Card{{Code: "AS", Pile: "discard"}}},
+			wantStatus: http.StatusOK,
+			wantCodes:  []string{"AS"},
+		},
+		{
+			name:       "whole pile",
+			body:       `{"to": "discard"}`,
+			s:          &mockDrawingService{},
+			wantStatus: http.StatusOK,
+		},
+		{name: "handles missing body", s: &mockDrawingService{}, wantStatus: http.StatusBadRequest},
+		{name: "handles card not in pile", body: `{"to": "discard"}`, s: &mockDrawingService{err: drawing.ErrCardNotInPile}, wantStatus: http.StatusBadRequest},
+		{name: "handles invalid pile", body: `{"to": ""}`, s: &mockDrawingService{err: drawing.ErrInvalidPile}, wantStatus: http.StatusBadRequest},
+		{name: "handles not found", body: `{"to": "discard"}`, s: &mockDrawingService{err: drawing.ErrNotFound}, wantStatus: http.StatusNotFound},
+		{name: "handles closed deck", body: `{"to": "discard"}`, s: &mockDrawingService{err: drawing.ErrDeckClosed}, wantStatus: http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := httprouter.New()
+			router.PATCH("/decks/:id/piles/:pile/move", moveCards(tt.s, "secret"))
+
+			req := httptest.NewRequest(http.MethodPatch, "/decks/test-test-test/piles/player1/move", bytes.NewBufferString(tt.body))
+			req.Header.Set(RequesterHeader, "dealer")
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("moveCards() status code %d, want %d", rr.Code, tt.wantStatus)
+				return
+			}
+
+			if http.StatusOK == rr.Code && ("player1" != tt.s.pile || "discard" != tt.s.to || !reflect.DeepEqual(tt.s.codes, tt.wantCodes) || "dealer" != tt.s.requester) {
+				t.Errorf("moveCards() passed %v from %q to %q by %q, want %v from player1 to discard by dealer", tt.s.codes, tt.s.pile, tt.s.to, tt.s.requester, tt.wantCodes)
+			}
+		})
+	}
+}
+
+func Test_returnPile(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		s           *mockDrawingService
+		wantStatus  int
+		wantShuffle bool
+	}{
+		{name: "without body", s: &mockDrawingService{}, wantStatus: http.StatusOK},
+		{name: "shuffled", body: `{"shuffle": true}`, s: &mockDrawingService{}, wantStatus: http.StatusOK, wantShuffle: true},
+		{name: "handles invalid body", body: `{"shuffle": "yes"}`, s: &mockDrawingService{}, wantStatus: http.StatusBadRequest},
+		{name: "handles invalid pile", s: &mockDrawingService{err: drawing.ErrInvalidPile}, wantStatus: http.StatusBadRequest},
+		{name: "handles not found", s: &mockDrawingService{err: drawing.ErrNotFound}, wantStatus: http.StatusNotFound},
+		{name: "handles closed deck", s: &mockDrawingService{err: drawing.ErrDeckClosed}, wantStatus: http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := httprouter.New()
+			router.PATCH("/decks/:id/piles/:pile/return", returnPile(tt.s, "secret"))
+
+			req := httptest.NewRequest(http.MethodPatch, "/decks/test-test-test/piles/dealer/return", bytes.NewBufferString(tt.body))
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("returnPile() status code %d, want %d", rr.Code, tt.wantStatus)
+				return
+			}
+
+			if http.StatusOK == rr.Code && ("dealer" != tt.s.pile || tt.wantShuffle != tt.s.shuffle) {
+				t.Errorf("returnPile() returned %q shuffle %v, want dealer shuffle %v", tt.s.pile, tt.s.shuffle, tt.wantShuffle)
+			}
+		})
+	}
+}
+
+func Test_dealCards(t *testing.T) {
+	hands := []drawing.Hand{
+		{Seat: "north", Cards: []drawing.Card{{Value: "ACE", Suit: "SPADES", Code: "AS", Pile: "north"}}},
+		{Seat: "south", Cards: []drawing.Card{{Value: "2", Suit: "SPADES", Code: "2S", Pile: "south"}}},
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		s          *mockDrawingService
+		wantStatus int
+		wantSeats  []string
+	}{
+		{
+			name:       "named seats",
+			body:       `{"seats": ["north", "south"], "cards": 1}`,
+			s:          &mockDrawingService{hands: hands},
+			wantStatus: http.StatusOK,
+			wantSeats:  []string{"north", "south"},
+		},
+		{
+			name:       "players",
+			body:       `{"players": 3, "cards": 5}`,
+			s:          &mockDrawingService{hands: hands},
+			wantStatus: http.StatusOK,
+			wantSeats:  []string{"player1", "player2", "player3"},
+		},
+		{name: "handles missing body", s: &mockDrawingService{}, wantStatus: http.StatusBadRequest},
+		{name: "handles no players", body: `{"cards": 5}`, s: &mockDrawingService{}, wantStatus: http.StatusBadRequest},
+		{name: "handles too many players", body: `{"players": 101, "cards": 1}`, s: &mockDrawingService{}, wantStatus: http.StatusBadRequest},
+		{name: "handles insufficient cards", body: `{"players": 9, "cards": 6}`, s: &mockDrawingService{err: drawing.ErrInsufficientRemainingCard}, wantStatus: http.StatusBadRequest},
+		{name: "handles invalid seats", body: `{"seats": ["N"], "cards": 1}`, s: &mockDrawingService{err: drawing.ErrInvalidPile}, wantStatus: http.StatusBadRequest},
+		{name: "handles not found", body: `{"players": 2, "cards": 1}`, s: &mockDrawingService{err: drawing.ErrNotFound}, wantStatus: http.StatusNotFound},
+		{name: "handles closed deck", body: `{"players": 2, "cards": 1}`, s: &mockDrawingService{err: drawing.ErrDeckClosed}, wantStatus: http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := httprouter.New()
+			router.PATCH("/decks/:id/deal", dealCards(tt.s))
+
+			req := httptest.NewRequest(http.MethodPatch, "/decks/test-test-test/deal", bytes.NewBufferString(tt.body))
+			req.Header.Set(RequesterHeader, "dealer")
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("dealCards() status code %d, want %d", rr.Code, tt.wantStatus)
+				return
+			}
+
+			if http.StatusOK != rr.Code {
+				return
+			}
+
+			if !reflect.DeepEqual(tt.s.seats, tt.wantSeats) || "dealer" != tt.s.requester {
+				t.Errorf("dealCards() dealt to %v by %q, want %v by dealer", tt.s.seats, tt.s.requester, tt.wantSeats)
+			}
+
+			var got []drawing.Hand
+			json.Unmarshal(rr.Body.Bytes(), &got)
+			if !reflect.DeepEqual(got, hands) {
+				t.Errorf("dealCards() = %v, want %v", got, hands)
+			}
+		})
+	}
+}
+
+func Test_drawCards_mode(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		token      string
+		s          *mockDrawingService
+		wantStatus int
+		wantOpts   drawing.Options
+	}{
+		{name: "top by default", s: &mockDrawingService{}, wantStatus: http.StatusOK},
+		{name: "bottom", query: "?mode=bottom", s: &mockDrawingService{}, wantStatus: http.StatusOK, wantOpts: drawing.Options{Mode: drawing.ModeBottom}},
+		{
+			name:       "specific",
+			query:      "?mode=specific&cards=AS,%20KH",
+			token:      "secret",
+			s:          &mockDrawingService{},
+			wantStatus: http.StatusOK,
+			wantOpts:   drawing.Options{Mode: drawing.ModeSpecific, Codes: []string{"AS", "KH"}},
+		},
+		{name: "specific/handles unauthorised", query: "?mode=specific&cards=AS,KH", s: &mockDrawingService{}, wantStatus: http.StatusUnauthorized},
+		{name: "specific/handles wrong token", query: "?mode=specific&cards=AS,KH", token: "wrong", s: &mockDrawingService{}, wantStatus: http.StatusUnauthorized},
+		{name: "handles invalid mode", query: "?mode=middle", s: &mockDrawingService{err: drawing.ErrInvalidMode}, wantStatus: http.StatusBadRequest},
+		{name: "handles card not available", query: "?mode=specific&cards=AS,KH", token: "secret", s: &mockDrawingService{err: drawing.ErrCardNotAvailable}, wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := httprouter.New()
+			router.PATCH("/decks/:id/draw/:amount", drawCards(tt.s, "secret"))
+
+			req := httptest.NewRequest(http.MethodPatch, "/decks/test-test-test/draw/2"+tt.query, nil)
+			if "" != tt.token {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("drawCards() status code %d, want %d", rr.Code, tt.wantStatus)
+				return
+			}
+
+			if http.StatusUnauthorized == rr.Code && "" != tt.s.opts.Mode {
+				t.Errorf("drawCards() drew %v, want no draw", tt.s.opts)
+			}
+
+			if http.StatusOK == rr.Code && !reflect.DeepEqual(tt.s.opts, tt.wantOpts) {
+				t.Errorf("drawCards() passed %v, want %v", tt.s.opts, tt.wantOpts)
+			}
+		})
+	}
+}
+
+func Test_burnCards(t *testing.T) {
+	tests := []struct {
+		name       string
+		amount     string
+		s          *mockDrawingService
+		wantStatus int
+	}{
+		{name: "valid", amount: "1", s: &mockDrawingService{}, wantStatus: http.StatusNoContent},
+		{name: "handles invalid amount", amount: "one", s: &mockDrawingService{}, wantStatus: http.StatusBadRequest},
+		{name: "handles insufficient cards", amount: "9", s: &mockDrawingService{err: drawing.ErrInsufficientRemainingCard}, wantStatus: http.StatusBadRequest},
+		{name: "handles not found", amount: "1", s: &mockDrawingService{err: drawing.ErrNotFound}, wantStatus: http.StatusNotFound},
+		{name: "handles closed deck", amount: "1", s: &mockDrawingService{err: drawing.ErrDeckClosed}, wantStatus: http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := httprouter.New()
+			router.PATCH("/decks/:id/burn/:amount", burnCards(tt.s))
+
+			req := httptest.NewRequest(http.MethodPatch, "/decks/test-test-test/burn/"+tt.amount, nil)
+			req.Header.Set(RequesterHeader, "dealer")
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("burnCards() status code %d, want %d", rr.Code, tt.wantStatus)
+				return
+			}
+
+			if http.StatusNoContent == rr.Code && (0 != rr.Body.Len() || 1 != tt.s.burned || "dealer" != tt.s.requester) {
+				t.Errorf("burnCards() burned %d by %q showing %q, want 1 by dealer showing nothing", tt.s.burned, tt.s.requester, rr.Body.String())
+			}
+		})
+	}
+}
+
+func Test_peekCards(t *testing.T) {
+	cards := []drawing.Card{{Value: "ACE", Suit: "SPADES", Code: "AS"}}
+
+	tests := []struct {
+		name       string
+		amount     string
+		token      string
+		s          *mockDrawingService
+		wantStatus int
+	}{
+		{name: "valid", amount: "1", token: "secret", s: &mockDrawingService{out: cards}, wantStatus: http.StatusOK},
+		{name: "handles unauthorised", amount: "52", s: &mockDrawingService{out: cards}, wantStatus: http.StatusUnauthorized},
+		{name: "handles wrong token", amount: "1", token: "wrong", s: &mockDrawingService{out: cards}, wantStatus: http.StatusUnauthorized},
+		{name: "handles invalid amount", amount: "one", token: "secret", s: &mockDrawingService{}, wantStatus: http.StatusBadRequest},
+		{name: "handles not found", amount: "1", token: "secret", s: &mockDrawingService{err: drawing.ErrNotFound}, wantStatus: http.StatusNotFound},
+		{name: "handles service error", amount: "1", token: "secret", s: &mockDrawingService{err: errors.New("test error")}, wantStatus: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := httprouter.New()
+			router.GET("/decks/:id/peek/:amount", peekCards(tt.s, "secret"))
+
+			req := httptest.NewRequest(http.MethodGet, "/decks/test-test-test/peek/"+tt.amount, nil)
+			req.Header.Set(RequesterHeader, "dealer")
+			if "" != tt.token {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("peekCards() status code %d, want %d", rr.Code, tt.wantStatus)
+				return
+			}
+
+			if http.StatusUnauthorized == rr.Code && "" != tt.s.requester {
+				t.Errorf("peekCards() peeked for %q, want no peek", tt.s.requester)
+			}
+
+			var got []drawing.Card
+			json.Unmarshal(rr.Body.Bytes(), &got)
+			if http.StatusOK == rr.Code && !reflect.DeepEqual(got, cards) {
+				t.Errorf("peekCards() = %v, want %v", got, cards)
+			}
+		})
+	}
+}
+
+func Test_drawCards_cutCard(t *testing.T) {
+	tests := []struct {
+		name       string
+		s          *mockDrawingService
+		wantHeader string
+	}{
+		{name: "before cut card", s: &mockDrawingService{}},
+		{name: "cut card reached", s: &mockDrawingService{reached: true}, wantHeader: "reached"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := httprouter.New()
+			router.PATCH("/decks/:id/draw/:amount", drawCards(tt.s, "secret"))
+
+			req := httptest.NewRequest(http.MethodPatch, "/decks/test-test-test/draw/1", nil)
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			if got := rr.Header().Get(CutCardHeader); http.StatusOK != rr.Code || tt.wantHeader != got {
+				t.Errorf("drawCards() status code %d, %s %q, want 200, %q", rr.Code, CutCardHeader, got, tt.wantHeader)
+			}
+		})
+	}
+}
+
+func Test_dealCards_cutCard(t *testing.T) {
+	tests := []struct {
+		name       string
+		s          *mockDrawingService
+		wantHeader string
+	}{
+		{name: "before cut card", s: &mockDrawingService{}},
+		{name: "cut card reached", s: &mockDrawingService{reached: true}, wantHeader: "reached"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := httprouter.New()
+			router.PATCH("/decks/:id/deal", dealCards(tt.s))
+
+			req := httptest.NewRequest(http.MethodPatch, "/decks/test-test-test/deal", bytes.NewBufferString(`{"players":2,"cards":1}`))
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			if got := rr.Header().Get(CutCardHeader); http.StatusOK != rr.Code || tt.wantHeader != got {
+				t.Errorf("dealCards() status code %d, %s %q, want 200, %q", rr.Code, CutCardHeader, got, tt.wantHeader)
+			}
+		})
+	}
+}
+
+func Test_burnCards_cutCard(t *testing.T) {
+	tests := []struct {
+		name       string
+		s          *mockDrawingService
+		wantHeader string
+	}{
+		{name: "before cut card", s: &mockDrawingService{}},
+		{name: "cut card reached", s: &mockDrawingService{reached: true}, wantHeader: "reached"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := httprouter.New()
+			router.PATCH("/decks/:id/burn/:amount", burnCards(tt.s))
+
+			req := httptest.NewRequest(http.MethodPatch, "/decks/test-test-test/burn/1", nil)
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			if got := rr.Header().Get(CutCardHeader); http.StatusNoContent != rr.Code || tt.wantHeader != got {
+				t.Errorf("burnCards() status code %d, %s %q, want 204, %q", rr.Code, CutCardHeader, got, tt.wantHeader)
+			}
+		})
+	}
+}
+
+func Test_cutDeck(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		s          *mockDrawingService
+		wantStatus int
+		want       string
+	}{
+		{name: "at position", body: `{"position": 20}`, s: &mockDrawingService{}, wantStatus: http.StatusOK, want: `{"position":20}`},
+		{name: "at random", s: &mockDrawingService{}, wantStatus: http.StatusOK, want: `{"position":1}`},
+		{name: "handles invalid body", body: `{"position": "top"}`, s: &mockDrawingService{}, wantStatus: http.StatusBadRequest},
+		{name: "handles invalid cut", body: `{"position": 60}`, s: &mockDrawingService{err: drawing.ErrInvalidCut}, wantStatus: http.StatusBadRequest},
+		{name: "handles not found", s: &mockDrawingService{err: drawing.ErrNotFound}, wantStatus: http.StatusNotFound},
+		{name: "handles closed deck", s: &mockDrawingService{err: drawing.ErrDeckClosed}, wantStatus: http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := httprouter.New()
+			router.PATCH("/decks/:id/cut", cutDeck(tt.s))
+
+			req := httptest.NewRequest(http.MethodPatch, "/decks/test-test-test/cut", bytes.NewBufferString(tt.body))
+			req.Header.Set(RequesterHeader, "dealer")
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("cutDeck() status code %d, want %d", rr.Code, tt.wantStatus)
+				return
+			}
+
+			if got := string(bytes.TrimSpace(rr.Body.Bytes())); http.StatusOK == rr.Code && (tt.want != got || "dealer" != tt.s.requester) {
+				t.Errorf("cutDeck() = %s by %q, want %s by dealer", got, tt.s.requester, tt.want)
+			}
+		})
+	}
+}
+
+func Test_burnPile(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		handler    func(s drawing.Service, adminToken string) func(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+		token      string
+		wantStatus int
+	}{
+		{name: "move", path: "/decks/:id/piles/:pile/move", handler: moveCards, token: "secret", wantStatus: http.StatusOK},
+		{name: "move/handles unauthorised", path: "/decks/:id/piles/:pile/move", handler: moveCards, wantStatus: http.StatusUnauthorized},
+		{name: "move/handles wrong token", path: "/decks/:id/piles/:pile/move", handler: moveCards, token: "wrong", wantStatus: http.StatusUnauthorized},
+		{name: "return", path: "/decks/:id/piles/:pile/return", handler: returnPile, token: "secret", wantStatus: http.StatusOK},
+		{name: "return/handles unauthorised", path: "/decks/:id/piles/:pile/return", handler: returnPile, wantStatus: http.StatusUnauthorized},
+		{name: "return/handles wrong token", path: "/decks/:id/piles/:pile/return", handler: returnPile, token: "wrong", wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &mockDrawingService{out: []drawing.Card{{Code: "AS", Pile: "discard"}}}
+			router := httprouter.New()
+			router.PATCH(tt.path, tt.handler(s, "secret"))
+
+			path := strings.NewReplacer(":id", "test-test-test", ":pile", drawing.BurnPile).Replace(tt.path)
+			req := httptest.NewRequest(http.MethodPatch, path, bytes.NewBufferString(`{"to": "discard"}`))
+			if "" != tt.token {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("%s status code %d, want %d", tt.path, rr.Code, tt.wantStatus)
+				return
+			}
+
+			if http.StatusUnauthorized == rr.Code && "" != s.pile {
+				t.Errorf("%s passed %q, want no call", tt.path, s.pile)
+			}
+		})
+	}
+}
+
+func Test_returnCards_burned(t *testing.T) {
+	cards := []drawing.Card{{Code: "AS", Pile: "player1"}, {Code: "KH", Pile: drawing.BurnPile}, {Code: "2C"}}
+
+	tests := []struct {
+		name  string
+		token string
+		want  []drawing.Card
+	}{
+		{name: "hides burned cards", want: []drawing.Card{cards[0], cards[2]}},
+		{name: "hides burned cards/wrong token", token: "wrong", want: []drawing.Card{cards[0], cards[2]}},
+		{name: "shows burned cards to authorised", token: "secret", want: cards},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := httprouter.New()
+			router.PATCH("/decks/:id/return", returnCards(&mockDrawingService{out: cards}, "secret"))
+
+			req := httptest.NewRequest(http.MethodPatch, "/decks/test-test-test/return", nil)
+			if "" != tt.token {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			var got []drawing.Card
+			json.Unmarshal(rr.Body.Bytes(), &got)
+			if http.StatusOK != rr.Code || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("returnCards() status code %d, got %v, want 200, %v", rr.Code, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_hideSecrets(t *testing.T) {
+	as := history.Card{ID: 1, Code: "AS"}
+	kh := history.Card{ID: 2, Code: "KH"}
+	qd := history.Card{ID: 3, Code: "QD"}
+
+	draws := []history.Draw{
+		{Number: 1, Action: history.ActionDraw, Cards: []history.Card{as}},
+		{Number: 2, Action: history.ActionPeek, Cards: []history.Card{kh}},
+		{Number: 3, Action: history.ActionBurn, Pile: drawing.BurnPile, Cards: []history.Card{kh, qd}},
+		{Number: 4, Action: history.ActionMove, Pile: "discard", Cards: []history.Card{kh}},
+		{Number: 5, Action: history.ActionReturn, Pile: "discard", Cards: []history.Card{kh}},
+		{Number: 6, Action: history.ActionReturn, Cards: []history.Card{as, qd}},
+		{Number: 7, Action: history.ActionShuffle},
+		{Number: 8, Action: history.ActionDraw, Cards: []history.Card{kh}},
+		{Number: 9, Action: history.ActionMove, Pile: "discard", Cards: []history.Card{kh}},
+	}
+	want := []history.Draw{
+		{Number: 1, Action: history.ActionDraw, Cards: []history.Card{as}},
+		{Number: 2, Action: history.ActionPeek, Cards: []history.Card{}},
+		{Number: 3, Action: history.ActionBurn, Pile: drawing.BurnPile, Cards: []history.Card{}},
+		{Number: 4, Action: history.ActionMove, Pile: "discard", Cards: []history.Card{}},
+		{Number: 5, Action: history.ActionReturn, Pile: "discard", Cards: []history.Card{}},
+		{Number: 6, Action: history.ActionReturn, Cards: []history.Card{as}},
+		{Number: 7, Action: history.ActionShuffle},
+		{Number: 8, Action: history.ActionDraw, Cards: []history.Card{kh}},
+		{Number: 9, Action: history.ActionMove, Pile: "discard", Cards: []history.Card{kh}},
+	}
+
+	hideSecrets(draws)
+	if !reflect.DeepEqual(draws, want) {
+		t.Errorf("hideSecrets() = %v, want %v", draws, want)
+	}
+}
+
+func Test_Handler_burnPile(t *testing.T) {
+	repository := memory.NewRepository()
+	router := Handler(
+		creating.NewService(repository),
+		listing.NewService(repository),
+		drawing.NewService(repository),
+		history.NewService(repository),
+		commitment.NewService(repository),
+		"secret",
+	)
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(method, path, bytes.NewBufferString(body)))
+		return rr
+	}
+
+	rr := serve(http.MethodPost, "/decks", `{}`)
+	var deck creating.Deck
+	if err := json.Unmarshal(rr.Body.Bytes(), &deck); err != nil {
+		t.Fatalf("POST /decks status code %d: %s", rr.Code, rr.Body.String())
+	}
+	path := "/decks/" + deck.ID.String()
+
+	if rr := serve(http.MethodPatch, path+"/burn/2", ""); http.StatusNoContent != rr.Code {
+		t.Fatalf("burn status code %d, want %d", rr.Code, http.StatusNoContent)
+	}
+
+	steps := []struct {
+		method     string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{method: http.MethodPatch, path: "/piles/burn/move", body: `{"to": "x"}`, wantStatus: http.StatusUnauthorized},
+		{method: http.MethodPatch, path: "/piles/burn/return", wantStatus: http.StatusUnauthorized},
+		{method: http.MethodPatch, path: "/piles/burn/draw/1", wantStatus: http.StatusBadRequest},
+	}
+	for _, st := range steps {
+		if rr := serve(st.method, path+st.path, st.body); st.wantStatus != rr.Code {
+			t.Errorf("%s %s status code %d, want %d", st.method, st.path, rr.Code, st.wantStatus)
+		}
+	}
+
+	var pile listing.Pile
+	json.Unmarshal(serve(http.MethodGet, path+"/piles/x", "").Body.Bytes(), &pile)
+	if 0 < len(pile.Cards) {
+		t.Errorf("pile x has %v, want no cards", pile.Cards)
+	}
+
+	rr = serve(http.MethodPatch, path+"/return", "")
+	if http.StatusOK != rr.Code || "[]\n" != rr.Body.String() {
+		t.Errorf("return status code %d, body %q, want 200, %q", rr.Code, rr.Body.String(), "[]\n")
+	}
+
+	var draws []history.Draw
+	json.Unmarshal(serve(http.MethodGet, path+"/draws", "").Body.Bytes(), &draws)
+	for _, d := range draws {
+		if 0 < len(d.Cards) {
+			t.Errorf("draws show %v cards %v", d.Action, d.Cards)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/srgyrn/lucky-38/pkg/commitment"
+)
+
+// FindReveal returns commitment, salt and state of the deck with given ID along with every card of the deck in the
+// order they were inserted, which is the order the deck was created with. If the deck is not found,
+// commitment.ErrNotFound is returned.
+func (r *Repository) FindReveal(deckID uuid.UUID) (commitment.Reveal, error) {
+	reveal := commitment.Reveal{DeckID: deckID}
+	var hash, salt sql.NullString
+	err := r.db.QueryRowContext(r.ctx, "SELECT commitment, salt, closed, remaining FROM decks WHERE deck_id = $1", deckID).
+		Scan(&hash, &salt, &reveal.Closed, &reveal.Remaining)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return commitment.Reveal{}, commitment.ErrNotFound
+		}
+
+		return commitment.Reveal{}, err
+	}
+	reveal.Commitment, reveal.Salt = hash.String, salt.String
+
+	rows, err := r.db.QueryContext(r.ctx, "SELECT card_id, code, value, suit, reversed FROM cards WHERE deck = $1 ORDER BY card_id", deckID)
+	if err != nil {
+		return commitment.Reveal{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c commitment.Card
+		if err := rows.Scan(&c.ID, &c.Code, &c.Value, &c.Suit, &c.Reversed); err != nil {
+			return commitment.Reveal{}, err
+		}
+
+		reveal.Cards = append(reveal.Cards, c)
+	}
+
+	return reveal, rows.Err()
+}
+
+// CloseDeck marks the deck with given ID as closed. If the deck is not found, commitment.ErrNotFound is returned.
+func (r *Repository) CloseDeck(deckID uuid.UUID) error {
+	result, err := r.db.ExecContext(r.ctx, "UPDATE decks SET closed = $1 WHERE deck_id = $2", true, deckID)
+	if err != nil {
+		return err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if 0 == updated {
+		return commitment.ErrNotFound
+	}
+
+	return nil
+}
+
+// nullString stores empty strings as NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: "" != s}
+}
@@ -0,0 +1,91 @@
+package storage_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/srgyrn/lucky-38/pkg/commitment"
+	"github.com/srgyrn/lucky-38/pkg/creating"
+	"github.com/srgyrn/lucky-38/pkg/drawing"
+)
+
+func TestRepository_FindReveal(t *testing.T) {
+	r := getRepository(t)
+	defer r.TestTeardown(t)
+
+	missingDeckID, _ := uuid.Parse("69077400-88cd-11eb-8dcd-0242ac130003")
+	if _, err := r.FindReveal(missingDeckID); !errors.Is(err, commitment.ErrNotFound) {
+		t.Errorf("FindReveal() want error = %v got %v", commitment.ErrNotFound, err)
+	}
+
+	if err := r.CloseDeck(missingDeckID); !errors.Is(err, commitment.ErrNotFound) {
+		t.Errorf("CloseDeck() want error = %v got %v", commitment.ErrNotFound, err)
+	}
+
+	seed := int64(3)
+	deck, err := creating.NewService(r).CreateDeck(creating.Deck{
+		Shuffled:  true,
+		Reversals: true,
+		Seed:      &seed,
+		Remaining: 4,
+		Cards:     []creating.Card{{Code: "AS"}, {Code: "2S"}, {Code: "3S"}, {Code: "4S"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateDeck() error = %v", err)
+	}
+
+	// reshuffling changes positions of the cards, reveal keeps the initial order
+	if _, err := drawing.NewService(r).Return(deck.ID.String(), nil, true, "dealer"); err != nil {
+		t.Fatalf("Return() error = %v", err)
+	}
+
+	if err := r.CloseDeck(deck.ID); err != nil {
+		t.Fatalf("CloseDeck() error = %v", err)
+	}
+
+	got, err := r.FindReveal(deck.ID)
+	if err != nil {
+		t.Fatalf("FindReveal() error = %v", err)
+	}
+
+	for i, c := range got.Cards {
+		if deck.Cards[i].ID != c.ID || deck.Cards[i].Reversed != c.Reversed {
+			t.Errorf("FindReveal() card %d = %v, want %v", i, c, deck.Cards[i])
+		}
+	}
+
+	if !got.Closed || 4 != got.Remaining || deck.Commitment != got.Commitment || deck.Salt != got.Salt {
+		t.Errorf("FindReveal() = %v, want closed deck with the commitment %s", got, deck.Commitment)
+	}
+
+	if !commitment.Verify(got.Commitment, got.Salt, got.Entries()) {
+		t.Errorf("FindReveal() cards %v do not match the commitment", got.Entries())
+	}
+
+	// turning a card over breaks the commitment
+	flipped := got
+	flipped.Cards = append([]commitment.Card(nil), got.Cards...)
+	flipped.Cards[0].Reversed = !flipped.Cards[0].Reversed
+	if commitment.Verify(got.Commitment, got.Salt, flipped.Entries()) {
+		t.Errorf("FindReveal() commitment matches flipped cards %v", flipped.Entries())
+	}
+
+	listed, err := r.Find(deck.ID)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+
+	if !listed.Closed || deck.Commitment != listed.Commitment {
+		t.Errorf("Find() = %v, want closed deck with the commitment %s", listed, deck.Commitment)
+	}
+
+	if _, _, err := drawing.NewService(r).Draw(deck.ID.String(), 1, "dealer"); !errors.Is(err, drawing.ErrDeckClosed) {
+		t.Errorf("Draw() error = %v, want %v", err, drawing.ErrDeckClosed)
+	}
+
+	if _, err := drawing.NewService(r).Return(deck.ID.String(), nil, false, "dealer"); !errors.Is(err, drawing.ErrDeckClosed) {
+		t.Errorf("Return() error = %v, want %v", err, drawing.ErrDeckClosed)
+	}
+}
@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/srgyrn/lucky-38/pkg/creating"
+)
+
+// Kinds of deck type symbols
+const (
+	symbolSuit = "suit"
+	symbolRank = "rank"
+)
+
+// CreateDeckType inserts a custom deck type, its suits, ranks and cards in order. If there is a deck type with the same name,
+// creating.ErrDefinitionExists is returned.
+func (r *Repository) CreateDeckType(t creating.DeckType) error {
+	tx, err := r.db.BeginTx(r.ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error at creating transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(r.ctx, "INSERT INTO deck_types (name) VALUES ($1) ON CONFLICT (name) DO NOTHING", t.Name)
+	if err != nil {
+		return err
+	}
+
+	if inserted, err := result.RowsAffected(); err != nil {
+		return err
+	} else if 0 == inserted {
+		return creating.ErrDefinitionExists
+	}
+
+	statement := "INSERT INTO deck_type_symbols (deck_type, kind, position, code, name) VALUES ($1, $2, $3, $4, $5)"
+	for kind, symbols := range map[string][]creating.Symbol{symbolSuit: t.Suits, symbolRank: t.Ranks} {
+		for i, s := range symbols {
+			if _, err := tx.ExecContext(r.ctx, statement, t.Name, kind, i, s.Code, s.Name); err != nil {
+				return fmt.Errorf("error at inserting %s %v, err: %v", kind, s, err)
+			}
+		}
+	}
+
+	statement = "INSERT INTO deck_type_cards (deck_type, position, code, value, suit, copy) VALUES ($1, $2, $3, $4, $5, $6)"
+	for i, c := range t.Cards {
+		if _, err := tx.ExecContext(r.ctx, statement, t.Name, i, c.Code, c.Value, c.Suit, c.Copy); err != nil {
+			return fmt.Errorf("error at inserting card %v, err: %v", c, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// FindDeckType returns the custom deck type with given name, its suits, ranks and cards in order. If the deck type is not found,
+// creating.ErrDefinitionNotFound is returned.
+func (r *Repository) FindDeckType(name string) (creating.DeckType, error) {
+	rows, err := r.db.QueryContext(r.ctx, "SELECT code, value, suit, copy FROM deck_type_cards WHERE deck_type = $1 ORDER BY position", name)
+	if err != nil {
+		return creating.DeckType{}, err
+	}
+	defer rows.Close()
+
+	t := creating.DeckType{Name: name}
+	for rows.Next() {
+		var c creating.Card
+		if err := rows.Scan(&c.Code, &c.Value, &c.Suit, &c.Copy); err != nil {
+			return creating.DeckType{}, err
+		}
+
+		t.Cards = append(t.Cards, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return creating.DeckType{}, err
+	}
+
+	// a deck type always has cards
+	if 0 == len(t.Cards) {
+		return creating.DeckType{}, creating.ErrDefinitionNotFound
+	}
+
+	symbols, err := r.db.QueryContext(r.ctx, "SELECT kind, code, name FROM deck_type_symbols WHERE deck_type = $1 ORDER BY kind, position", name)
+	if err != nil {
+		return creating.DeckType{}, err
+	}
+	defer symbols.Close()
+
+	for symbols.Next() {
+		var kind string
+		var s creating.Symbol
+		if err := symbols.Scan(&kind, &s.Code, &s.Name); err != nil {
+			return creating.DeckType{}, err
+		}
+
+		if symbolSuit == kind {
+			t.Suits = append(t.Suits, s)
+		} else {
+			t.Ranks = append(t.Ranks, s)
+		}
+	}
+
+	return t, symbols.Err()
+}
@@ -0,0 +1,45 @@
+package storage_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/srgyrn/lucky-38/pkg/creating"
+)
+
+func TestRepository_DeckType(t *testing.T) {
+	r := getRepository(t)
+	defer r.TestTeardown(t)
+
+	if _, err := r.FindDeckType("colors"); !errors.Is(err, creating.ErrDefinitionNotFound) {
+		t.Errorf("FindDeckType() error = %v, want %v", err, creating.ErrDefinitionNotFound)
+	}
+
+	want := creating.DeckType{
+		Name:  "colors",
+		Suits: []creating.Symbol{{Code: "R", Name: "RED"}, {Code: "B", Name: "BLUE"}},
+		Ranks: []creating.Symbol{{Code: "1", Name: "ONE"}},
+		Cards: []creating.Card{
+			{Code: "1R", Value: "ONE", Suit: "RED", Copy: 1},
+			{Code: "1R", Value: "ONE", Suit: "RED", Copy: 2},
+			{Code: "1B", Value: "ONE", Suit: "BLUE"},
+		},
+	}
+	if err := r.CreateDeckType(want); err != nil {
+		t.Fatalf("CreateDeckType() error = %v", err)
+	}
+
+	if err := r.CreateDeckType(want); !errors.Is(err, creating.ErrDefinitionExists) {
+		t.Errorf("CreateDeckType() error = %v, want %v", err, creating.ErrDefinitionExists)
+	}
+
+	got, err := r.FindDeckType("colors")
+	if err != nil {
+		t.Fatalf("FindDeckType() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindDeckType() got = %v, want %v", got, want)
+	}
+}
@@ -1,23 +1,74 @@
 package storage
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/google/uuid"
+
+	"github.com/srgyrn/lucky-38/pkg/config"
 )
 
-func (r *Repository) TestTeardown(t *testing.T) {
-	if _, err := r.db.Exec("TRUNCATE cards"); err != nil {
-		t.Fatalf("TRUNCATE cards err: %v", err)
+// NewTestRepository connects to the database configured in .env.test when APP_ENV is test.
+// Otherwise, it opens a new SQLite database in a temporary directory.
+func NewTestRepository(t *testing.T) *Repository {
+	t.Helper()
+	driver, source := config.DriverSQLite, "file:"+filepath.Join(t.TempDir(), "lucky_test.db")
+
+	if "test" == os.Getenv("APP_ENV") {
+		conf, err := config.Load("../../")
+		if err != nil {
+			t.Fatalf("config.Load() err: %v", err)
+		}
+
+		if conf.Driver == "" || conf.Source == "" {
+			t.Fatalf("unexpected value for driver(%s) or source(%s)", conf.Driver, conf.Source)
+		}
+
+		driver, source = conf.Driver, conf.Source
+	}
+
+	r, err := NewRepository(driver, source)
+	if err != nil {
+		t.Fatalf("storage.NewRepository() error = %v", err)
 	}
 
-	if _, err := r.db.Exec("ALTER SEQUENCE cards_card_id_seq RESTART WITH 1"); err != nil {
-		t.Fatalf("resetting card_id failed, err: %v", err)
+	if _, err := r.MigrateUp(); err != nil {
+		t.Fatalf("MigrateUp() error = %v", err)
+	}
+
+	return r
+}
+
+func (r *Repository) TestTeardown(t *testing.T) {
+	if config.DriverSQLite == r.driver {
+		if _, err := r.db.Exec("DELETE FROM cards"); err != nil {
+			t.Fatalf("DELETE FROM cards err: %v", err)
+		}
+
+		if _, err := r.db.Exec("DELETE FROM sqlite_sequence WHERE name = 'cards'"); err != nil {
+			t.Fatalf("resetting card_id failed, err: %v", err)
+		}
+	} else {
+		if _, err := r.db.Exec("TRUNCATE cards"); err != nil {
+			t.Fatalf("TRUNCATE cards err: %v", err)
+		}
+
+		if _, err := r.db.Exec("ALTER SEQUENCE cards_card_id_seq RESTART WITH 1"); err != nil {
+			t.Fatalf("resetting card_id failed, err: %v", err)
+		}
 	}
 
 	if _, err := r.db.Exec("DELETE FROM decks"); err != nil {
 		t.Fatalf("DELETE FROM decks err: %v", err)
 	}
+
+	for _, table := range []string{"deck_type_symbols", "deck_type_cards", "deck_types"} {
+		if _, err := r.db.Exec("DELETE FROM " + table); err != nil {
+			t.Fatalf("DELETE FROM %s err: %v", table, err)
+		}
+	}
 }
 
 func (r *Repository) TestCountCards(t *testing.T) int {
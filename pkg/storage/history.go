@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/srgyrn/lucky-38/pkg/drawing"
+	"github.com/srgyrn/lucky-38/pkg/history"
+)
+
+// FindDraws returns draws, returns and moves of the deck with given ID in the order they took place, draws without
+// cards have an empty list of cards. If the deck is not found, history.ErrNotFound is returned.
+func (r *Repository) FindDraws(deckID uuid.UUID) ([]history.Draw, error) {
+	var exists int
+	err := r.db.QueryRowContext(r.ctx, "SELECT 1 FROM decks WHERE deck_id = $1", deckID).Scan(&exists)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return []history.Draw{}, history.ErrNotFound
+		}
+
+		return []history.Draw{}, err
+	}
+
+	rows, err := r.db.QueryContext(r.ctx, "SELECT draw_id, number, action, pile, position, requester, drawn_at FROM draws WHERE deck = $1 ORDER BY number", deckID)
+	if err != nil {
+		return []history.Draw{}, err
+	}
+	defer rows.Close()
+
+	draws := []history.Draw{}
+	index := make(map[uuid.UUID]int)
+	for rows.Next() {
+		draw := history.Draw{DeckID: deckID, Cards: []history.Card{}}
+		if err := rows.Scan(&draw.ID, &draw.Number, &draw.Action, &draw.Pile, &draw.Position, &draw.Requester, &draw.DrawnAt); err != nil {
+			return []history.Draw{}, err
+		}
+
+		index[draw.ID] = len(draws)
+		draws = append(draws, draw)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []history.Draw{}, err
+	}
+
+	query := `SELECT dc.draw_id, dc.card_id, dc.code, dc.value, dc.suit, dc.copy, dc.reversed FROM draw_cards dc
+    INNER JOIN draws d ON d.draw_id = dc.draw_id WHERE d.deck = $1 ORDER BY d.number, dc.seq`
+	cardRows, err := r.db.QueryContext(r.ctx, query, deckID)
+	if err != nil {
+		return []history.Draw{}, err
+	}
+	defer cardRows.Close()
+
+	for cardRows.Next() {
+		var drawID uuid.UUID
+		var card history.Card
+		if err := cardRows.Scan(&drawID, &card.ID, &card.Code, &card.Value, &card.Suit, &card.Copy, &card.Reversed); err != nil {
+			return []history.Draw{}, err
+		}
+
+		i := index[drawID]
+		draws[i].Cards = append(draws[i].Cards, card)
+	}
+
+	return draws, cardRows.Err()
+}
+
+// recordDraw inserts a draw of given action, pile and cards, in the order they are dealt, to the history of the deck.
+// It is meant to be called while the deck is locked by tx, so numbering draws cannot race.
+func (r *Repository) recordDraw(tx *sql.Tx, deckID uuid.UUID, action, pile, requester string, cards []drawing.Card) error {
+	return r.recordDrawAt(tx, deckID, action, pile, requester, 0, cards)
+}
+
+// recordDrawAt inserts a draw like recordDraw does, along with the position of a cut.
+func (r *Repository) recordDrawAt(tx *sql.Tx, deckID uuid.UUID, action, pile, requester string, position int, cards []drawing.Card) error {
+	var number int
+	err := tx.QueryRowContext(r.ctx, "SELECT COALESCE(MAX(number), 0) + 1 FROM draws WHERE deck = $1", deckID).Scan(&number)
+	if err != nil {
+		return err
+	}
+
+	drawID := uuid.New()
+	statement := "INSERT INTO draws (draw_id, deck, number, action, pile, position, requester, drawn_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)"
+	if _, err := tx.ExecContext(r.ctx, statement, drawID, deckID, number, action, pile, position, requester, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	statement = "INSERT INTO draw_cards (draw_id, seq, card_id, code, value, suit, copy, reversed) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)"
+	for i, c := range cards {
+		if _, err := tx.ExecContext(r.ctx, statement, drawID, i+1, c.ID, c.Code, c.Value, c.Suit, c.Copy, c.Reversed); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
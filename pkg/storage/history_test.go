@@ -0,0 +1,243 @@
+package storage_test
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/srgyrn/lucky-38/pkg/creating"
+	"github.com/srgyrn/lucky-38/pkg/drawing"
+	"github.com/srgyrn/lucky-38/pkg/history"
+)
+
+func TestRepository_FindDraws(t *testing.T) {
+	r := getRepository(t)
+	defer r.TestTeardown(t)
+
+	migration := getMigrationSQL(t, filepath.Join("testdata", "migrations", "insert_deck.sql"))
+	r.TestInitData(t, migration)
+	deckID, _ := uuid.Parse("a251071b-662f-44b6-ba11-e24863039c59")
+
+	t.Run("missing deck", func(t *testing.T) {
+		missingDeckID, _ := uuid.Parse("69077400-88cd-11eb-8dcd-0242ac130003")
+		if _, err := r.FindDraws(missingDeckID); !errors.Is(err, history.ErrNotFound) {
+			t.Errorf("FindDraws() want error = %v got %v", history.ErrNotFound, err)
+		}
+	})
+
+	t.Run("no draws", func(t *testing.T) {
+		got, err := r.FindDraws(deckID)
+		if err != nil {
+			t.Fatalf("FindDraws() error = %v", err)
+		}
+
+		if 0 != len(got) {
+			t.Errorf("FindDraws() = %v, want no draws", got)
+		}
+	})
+
+	t.Run("draws in order", func(t *testing.T) {
+		s := drawing.NewService(r)
+		for _, d := range []struct {
+			n         int
+			requester string
+		}{{2, "dealer"}, {1, "player1"}} {
+			if _, _, err := s.Draw(deckID.String(), d.n, d.requester); err != nil {
+				t.Fatalf("Draw() error = %v", err)
+			}
+		}
+
+		got, err := r.FindDraws(deckID)
+		if err != nil {
+			t.Fatalf("FindDraws() error = %v", err)
+		}
+
+		want := []history.Draw{
+			{
+				DeckID:    deckID,
+				Number:    1,
+				Action:    history.ActionDraw,
+				Requester: "dealer",
+				Cards: []history.Card{
+					{ID: 1, Code: "AS", Value: "ACE", Suit: "SPADES"},
+					{ID: 2, Code: "2S", Value: "2", Suit: "SPADES"},
+				},
+			},
+			{
+				DeckID:    deckID,
+				Number:    2,
+				Action:    history.ActionDraw,
+				Requester: "player1",
+				Cards: []history.Card{
+					{ID: 3, Code: "3S", Value: "3", Suit: "SPADES"},
+				},
+			},
+		}
+
+		if len(got) != len(want) {
+			t.Fatalf("FindDraws() = %v, want %v", got, want)
+		}
+
+		for i := range got {
+			if got[i].ID == uuid.Nil || got[i].DrawnAt.IsZero() {
+				t.Errorf("FindDraws() draw %d misses id or time: %v", i, got[i])
+			}
+			want[i].ID, want[i].DrawnAt = got[i].ID, got[i].DrawnAt
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("FindDraws() = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestRepository_FindDraws_shoe(t *testing.T) {
+	r := getRepository(t)
+	defer r.TestTeardown(t)
+
+	deck, err := creating.NewService(r).CreateDeck(creating.Deck{Remaining: creating.FrenchDeckCardTotal, Decks: 2})
+	if err != nil {
+		t.Fatalf("CreateDeck() error = %v", err)
+	}
+
+	if 2*creating.FrenchDeckCardTotal != deck.Remaining {
+		t.Fatalf("CreateDeck() remaining = %d, want %d", deck.Remaining, 2*creating.FrenchDeckCardTotal)
+	}
+
+	// the first deck is dealt before the second one as the shoe is not shuffled
+	cards, _, err := drawing.NewService(r).Draw(deck.ID.String(), creating.FrenchDeckCardTotal+1, "dealer")
+	if err != nil {
+		t.Fatalf("Draw() error = %v", err)
+	}
+
+	first, last := cards[0], cards[len(cards)-1]
+	if first.Code != last.Code || 1 != first.Copy || 2 != last.Copy {
+		t.Errorf("Draw() first %v and last %v, want the same card from both decks", first, last)
+	}
+
+	draws, err := r.FindDraws(deck.ID)
+	if err != nil {
+		t.Fatalf("FindDraws() error = %v", err)
+	}
+
+	got := draws[0].Cards
+	want := []history.Card{
+		{ID: first.ID, Code: first.Code, Value: first.Value, Suit: first.Suit, Copy: 1},
+		{ID: last.ID, Code: last.Code, Value: last.Value, Suit: last.Suit, Copy: 2},
+	}
+	if !reflect.DeepEqual([]history.Card{got[0], got[len(got)-1]}, want) {
+		t.Errorf("FindDraws() cards %v, want to start with %v and end with %v", got, want[0], want[1])
+	}
+
+	listed, err := r.Find(deck.ID)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+
+	if 2 != listed.Cards[0].Copy {
+		t.Errorf("Find() first card %v, want it from the second deck", listed.Cards[0])
+	}
+}
+
+func TestRepository_FindDraws_reversed(t *testing.T) {
+	r := getRepository(t)
+	defer r.TestTeardown(t)
+
+	seed := int64(38)
+	deck, err := creating.NewService(r).CreateDeck(creating.Deck{
+		Type:      creating.TarotDeck,
+		Shuffled:  true,
+		Reversals: true,
+		Seed:      &seed,
+		Remaining: creating.FrenchDeckCardTotal,
+	})
+	if err != nil {
+		t.Fatalf("CreateDeck() error = %v", err)
+	}
+
+	// long names of the major arcana fit the cards table
+	listed, err := r.Find(deck.ID)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+
+	var want []bool
+	for i, c := range deck.Cards {
+		want = append(want, c.Reversed)
+		if l := listed.Cards[i]; c.Code != l.Code || c.Value != l.Value || c.Reversed != l.Reversed {
+			t.Errorf("Find() card %d = %v, want %v", i, l, c)
+		}
+	}
+
+	cards, _, err := drawing.NewService(r).Draw(deck.ID.String(), len(deck.Cards), "reader")
+	if err != nil {
+		t.Fatalf("Draw() error = %v", err)
+	}
+
+	draws, err := r.FindDraws(deck.ID)
+	if err != nil {
+		t.Fatalf("FindDraws() error = %v", err)
+	}
+
+	for i := range cards {
+		if want[i] != cards[i].Reversed || want[i] != draws[0].Cards[i].Reversed {
+			t.Errorf("card %d reversed %v in draw, %v in history, want %v", i, cards[i].Reversed, draws[0].Cards[i].Reversed, want[i])
+		}
+	}
+}
+
+func TestRepository_peekAndBurn(t *testing.T) {
+	r := getRepository(t)
+	defer r.TestTeardown(t)
+
+	migration := getMigrationSQL(t, filepath.Join("testdata", "migrations", "insert_deck.sql"))
+	r.TestInitData(t, migration)
+	deckID, _ := uuid.Parse("a251071b-662f-44b6-ba11-e24863039c59")
+	s := drawing.NewService(r)
+
+	peeked, err := s.Peek(deckID.String(), 2, "dealer")
+	if err != nil {
+		t.Fatalf("Peek() error = %v", err)
+	}
+
+	if 2 != len(peeked) || "AS" != peeked[0].Code || 4 != r.TestDeckRemaining(t, deckID) {
+		t.Errorf("Peek() = %v leaving %d cards, want AS,2S leaving 4", peeked, r.TestDeckRemaining(t, deckID))
+	}
+
+	burned, _, err := s.Burn(deckID.String(), 1, "dealer")
+	if err != nil || 1 != burned {
+		t.Fatalf("Burn() = %d, %v, want 1", burned, err)
+	}
+
+	cards, _, err := s.Draw(deckID.String(), 1, "player1")
+	if err != nil || "2S" != cards[0].Code {
+		t.Fatalf("Draw() = %v, %v, want 2S after the burned card", cards, err)
+	}
+
+	pile, err := r.FindPile(deckID, drawing.BurnPile)
+	if err != nil || 1 != len(pile) || "AS" != pile[0].Code {
+		t.Errorf("FindPile() = %v, %v, want the burned AS", pile, err)
+	}
+
+	draws, err := r.FindDraws(deckID)
+	if err != nil {
+		t.Fatalf("FindDraws() error = %v", err)
+	}
+
+	var got []history.Draw
+	for _, d := range draws {
+		got = append(got, history.Draw{Action: d.Action, Pile: d.Pile, Requester: d.Requester})
+	}
+
+	want := []history.Draw{
+		{Action: history.ActionPeek, Requester: "dealer"},
+		{Action: history.ActionBurn, Pile: drawing.BurnPile, Requester: "dealer"},
+		{Action: history.ActionDraw, Requester: "player1"},
+	}
+	if !reflect.DeepEqual(got, want) || 2 != len(draws[0].Cards) {
+		t.Errorf("FindDraws() = %v, want %v with 2 cards peeked", got, want)
+	}
+}
@@ -0,0 +1,609 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/srgyrn/lucky-38/pkg/commitment"
+	"github.com/srgyrn/lucky-38/pkg/creating"
+	"github.com/srgyrn/lucky-38/pkg/drawing"
+	"github.com/srgyrn/lucky-38/pkg/history"
+	"github.com/srgyrn/lucky-38/pkg/listing"
+)
+
+type (
+	// Repository keeps decks and cards in memory and implements creating.Repository, listing.Repository
+	// and drawing.Repository with the same semantics as storage.Repository.
+	Repository struct {
+		mu        sync.RWMutex
+		lastID    int
+		decks     map[uuid.UUID]*deck
+		deckTypes map[string]creating.DeckType
+	}
+
+	deck struct {
+		shuffled   bool
+		remaining  int
+		seed       *int64
+		commitment string
+		salt       string
+		closed     bool
+		cutCard    int
+		cards      []card
+		draws      []history.Draw
+	}
+
+	card struct {
+		id       int
+		code     string
+		value    string
+		suit     string
+		drawn    bool
+		position int
+		copy     int
+		reversed bool
+		pile     string
+	}
+)
+
+func NewRepository() *Repository {
+	return &Repository{decks: make(map[uuid.UUID]*deck), deckTypes: make(map[string]creating.DeckType)}
+}
+
+// CreateDeck stores a new deck and its cards, assigning IDs the way the cards table sequence would.
+// The first card is placed on top of the deck.
+func (r *Repository) CreateDeck(d *creating.Deck) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d.ID = uuid.New()
+	stored := &deck{shuffled: d.Shuffled, remaining: d.Remaining, seed: d.Seed, commitment: d.Commitment, salt: d.Salt, cutCard: d.CutCard}
+
+	var result []creating.Card
+	for i, c := range d.Cards {
+		r.lastID++
+		c.ID = r.lastID
+		stored.cards = append(stored.cards, card{id: c.ID, code: c.Code, value: c.Value, suit: c.Suit, position: i, copy: c.Copy, reversed: c.Reversed})
+		result = append(result, c)
+	}
+
+	r.decks[d.ID] = stored
+	d.Cards = result
+
+	return nil
+}
+
+// Find returns listing.Deck with its available cards, top of the deck first, if found.
+func (r *Repository) Find(ID uuid.UUID) (listing.Deck, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	d, ok := r.decks[ID]
+	if !ok {
+		return listing.Deck{}, listing.ErrNotFound
+	}
+
+	deck := listing.Deck{ID: ID, Shuffled: d.shuffled, Remaining: d.remaining, Seed: d.seed, Commitment: d.commitment, Closed: d.closed, CutCard: d.cutCard}
+	for _, c := range d.availableCards() {
+		deck.Cards = append(deck.Cards, toListing(c))
+	}
+
+	for _, c := range d.cards {
+		if c.drawn && "" != c.pile {
+			if nil == deck.Piles {
+				deck.Piles = make(listing.Piles)
+			}
+			deck.Piles[c.pile]++
+		}
+	}
+
+	return deck, nil
+}
+
+// FindPile returns cards in the pile with given name of the deck with given ID, in the order they were put in it.
+func (r *Repository) FindPile(ID uuid.UUID, name string) ([]listing.Card, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	d, ok := r.decks[ID]
+	if !ok {
+		return nil, listing.ErrNotFound
+	}
+
+	var cards []listing.Card
+	for _, c := range d.drawnCards() {
+		if name == c.Pile {
+			cards = append(cards, toListing(c))
+		}
+	}
+
+	return cards, nil
+}
+
+// FindAvailableCardByDeckID finds cards that are not drawn from the deck with given ID, top of the deck first
+func (r *Repository) FindAvailableCardByDeckID(deckID uuid.UUID) ([]drawing.Card, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	d, ok := r.decks[deckID]
+	if !ok {
+		return nil, drawing.ErrNotFound
+	}
+
+	cards := d.availableCards()
+	if 0 == len(cards) {
+		return cards, drawing.ErrNotFound
+	}
+
+	return cards, nil
+}
+
+// DrawCards passes available cards of the deck with ID deckID to pick, marks the picked cards as drawn, puts them in
+// pile unless it is empty, decreases remaining accordingly and records the draw. The repository stays locked meanwhile.
+func (r *Repository) DrawCards(deckID uuid.UUID, pile, requester string, pick drawing.Pick) ([]drawing.Card, error) {
+	return r.drawCards(deckID, history.ActionDraw, pile, requester, pick)
+}
+
+// BurnCards draws the cards picked among available cards of the deck with ID deckID into drawing.BurnPile like
+// DrawCards does, recording a burn.
+func (r *Repository) BurnCards(deckID uuid.UUID, requester string, pick drawing.Pick) ([]drawing.Card, error) {
+	return r.drawCards(deckID, history.ActionBurn, drawing.BurnPile, requester, pick)
+}
+
+// PeekCards passes available cards of the deck with ID deckID to pick and records a peek at the picked cards.
+// Cards stay as they are.
+func (r *Repository) PeekCards(deckID uuid.UUID, requester string, pick drawing.Pick) ([]drawing.Card, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d, ok := r.decks[deckID]
+	if !ok {
+		return []drawing.Card{}, drawing.ErrNotFound
+	}
+
+	if d.closed {
+		return []drawing.Card{}, drawing.ErrDeckClosed
+	}
+
+	available := d.availableCards()
+	if 0 == len(available) {
+		return []drawing.Card{}, drawing.ErrNotFound
+	}
+
+	cards, err := pick(available)
+	if err != nil {
+		return []drawing.Card{}, err
+	}
+
+	d.record(deckID, history.ActionPeek, "", requester, cards)
+
+	return cards, nil
+}
+
+// drawCards draws the cards picked among available cards of the deck with ID deckID into pile, unless it is empty,
+// and records them as given action
+func (r *Repository) drawCards(deckID uuid.UUID, action, pile, requester string, pick drawing.Pick) ([]drawing.Card, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d, ok := r.decks[deckID]
+	if !ok {
+		return []drawing.Card{}, drawing.ErrNotFound
+	}
+
+	if d.closed {
+		return []drawing.Card{}, drawing.ErrDeckClosed
+	}
+
+	available := d.availableCards()
+	if 0 == len(available) {
+		return []drawing.Card{}, drawing.ErrNotFound
+	}
+
+	cards, err := pick(available)
+	if err != nil {
+		return []drawing.Card{}, err
+	}
+
+	d.markDrawn(cards)
+	if "" != pile {
+		cards = d.pileCards(pile, cards)
+	}
+
+	d.record(deckID, action, pile, requester, cards)
+
+	return cards, nil
+}
+
+// markDrawn marks given available cards as drawn and decreases remaining accordingly
+func (d *deck) markDrawn(cards []drawing.Card) {
+	picked := make(map[int]bool, len(cards))
+	for _, c := range cards {
+		picked[c.ID] = true
+	}
+
+	for i := range d.cards {
+		if picked[d.cards[i].id] && !d.cards[i].drawn {
+			d.cards[i].drawn = true
+			d.remaining--
+		}
+	}
+}
+
+// DealCards passes available cards of the deck with ID deckID to split, marks the cards of the hands as drawn, puts
+// each hand in the pile of its seat and records every hand. The repository stays locked meanwhile.
+func (r *Repository) DealCards(deckID uuid.UUID, requester string, split drawing.Split) ([]drawing.Hand, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d, ok := r.decks[deckID]
+	if !ok {
+		return []drawing.Hand{}, drawing.ErrNotFound
+	}
+
+	if d.closed {
+		return []drawing.Hand{}, drawing.ErrDeckClosed
+	}
+
+	available := d.availableCards()
+	if 0 == len(available) {
+		return []drawing.Hand{}, drawing.ErrNotFound
+	}
+
+	hands, err := split(available)
+	if err != nil {
+		return []drawing.Hand{}, err
+	}
+
+	for i, h := range hands {
+		if 0 == len(h.Cards) {
+			continue
+		}
+
+		d.markDrawn(h.Cards)
+		hands[i].Cards = d.pileCards(h.Seat, h.Cards)
+		d.record(deckID, history.ActionDeal, h.Seat, requester, hands[i].Cards)
+	}
+
+	return hands, nil
+}
+
+// ReturnCards passes drawn cards of the deck with ID deckID to pick, puts the picked cards back to the deck out of
+// their piles, increases remaining accordingly and records the return from pile. Then, available cards are reordered
+// by arrange, recording a shuffle if shuffled is set. The deck is changed only once arrange succeeds, and the
+// repository stays locked meanwhile.
+func (r *Repository) ReturnCards(deckID uuid.UUID, pile, requester string, shuffled bool, pick drawing.Pick, arrange drawing.Arrange) ([]drawing.Card, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d, ok := r.decks[deckID]
+	if !ok {
+		return []drawing.Card{}, drawing.ErrNotFound
+	}
+
+	if d.closed {
+		return []drawing.Card{}, drawing.ErrDeckClosed
+	}
+
+	cards, err := pick(d.drawnCards())
+	if err != nil {
+		return []drawing.Card{}, err
+	}
+
+	picked := make(map[int]bool, len(cards))
+	for _, c := range cards {
+		picked[c.ID] = true
+	}
+
+	// changes are staged on a copy of the deck, so a failing arrange leaves the deck as it is
+	staged := *d
+	staged.cards = append([]card(nil), d.cards...)
+	for i := range staged.cards {
+		if picked[staged.cards[i].id] && staged.cards[i].drawn {
+			staged.cards[i].drawn, staged.cards[i].pile = false, ""
+			staged.remaining++
+		}
+	}
+
+	available := staged.availableCards()
+	arranged, err := arrange(append([]drawing.Card(nil), available...))
+	if err != nil {
+		return []drawing.Card{}, fmt.Errorf("error at arranging cards: %v", err)
+	}
+
+	if len(arranged) != len(available) {
+		return []drawing.Card{}, fmt.Errorf("error at arranging cards: got %d cards, want %d", len(arranged), len(available))
+	}
+
+	staged.arrangeCards(arranged)
+	if 0 < len(cards) {
+		staged.record(deckID, history.ActionReturn, pile, requester, cards)
+	}
+
+	if shuffled {
+		staged.record(deckID, history.ActionShuffle, "", requester, nil)
+	}
+	*d = staged
+
+	return cards, nil
+}
+
+// CutCards reorders available cards of the deck with ID deckID as rotate returns them and records the cut at the
+// position rotate returns.
+// The repository stays locked meanwhile.
+func (r *Repository) CutCards(deckID uuid.UUID, requester string, rotate drawing.Rotate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d, ok := r.decks[deckID]
+	if !ok {
+		return drawing.ErrNotFound
+	}
+
+	if d.closed {
+		return drawing.ErrDeckClosed
+	}
+
+	available := d.availableCards()
+	rotated, position, err := rotate(append([]drawing.Card(nil), available...))
+	if err != nil {
+		return err
+	}
+
+	if len(rotated) != len(available) {
+		return fmt.Errorf("error at cutting cards: got %d cards, want %d", len(rotated), len(available))
+	}
+
+	d.arrangeCards(rotated)
+	d.recordAt(deckID, history.ActionCut, "", requester, position, nil)
+
+	return nil
+}
+
+// FindCutCard returns the number of available cards left when the cut card of the deck with given ID is reached.
+func (r *Repository) FindCutCard(deckID uuid.UUID) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	d, ok := r.decks[deckID]
+	if !ok {
+		return 0, drawing.ErrNotFound
+	}
+
+	return d.cutCard, nil
+}
+
+// arrangeCards sets position of given cards to their index, so they are dealt in the given order
+func (d *deck) arrangeCards(cards []drawing.Card) {
+	positions := make(map[int]int, len(cards))
+	for i, c := range cards {
+		positions[c.ID] = i
+	}
+
+	for i := range d.cards {
+		if position, ok := positions[d.cards[i].id]; ok {
+			d.cards[i].position = position
+		}
+	}
+}
+
+// MoveCards passes drawn cards of the deck with ID deckID to pick, puts the picked cards on top of pile and records
+// the move. The repository stays locked meanwhile.
+func (r *Repository) MoveCards(deckID uuid.UUID, pile, requester string, pick drawing.Pick) ([]drawing.Card, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d, ok := r.decks[deckID]
+	if !ok {
+		return []drawing.Card{}, drawing.ErrNotFound
+	}
+
+	if d.closed {
+		return []drawing.Card{}, drawing.ErrDeckClosed
+	}
+
+	cards, err := pick(d.drawnCards())
+	if err != nil {
+		return []drawing.Card{}, err
+	}
+
+	if 0 == len(cards) {
+		return cards, nil
+	}
+
+	cards = d.pileCards(pile, cards)
+	d.record(deckID, history.ActionMove, pile, requester, cards)
+
+	return cards, nil
+}
+
+// pileCards puts given drawn cards on top of pile in the given order and returns them in the pile. Positions of drawn
+// cards order their piles, so the cards are given positions above the top card of the pile.
+func (d *deck) pileCards(pile string, cards []drawing.Card) []drawing.Card {
+	top := 0
+	for _, c := range d.cards {
+		if c.drawn && pile == c.pile && top <= c.position {
+			top = c.position + 1
+		}
+	}
+
+	positions := make(map[int]int, len(cards))
+	var piled []drawing.Card
+	for i, c := range cards {
+		positions[c.ID] = top + i
+		c.Pile = pile
+		piled = append(piled, c)
+	}
+
+	for i := range d.cards {
+		if position, ok := positions[d.cards[i].id]; ok {
+			d.cards[i].pile, d.cards[i].position = pile, position
+		}
+	}
+
+	return piled
+}
+
+// record appends an action on given cards to the history of the deck
+func (d *deck) record(deckID uuid.UUID, action, pile, requester string, cards []drawing.Card) {
+	d.recordAt(deckID, action, pile, requester, 0, cards)
+}
+
+// recordAt appends an action like record does, along with the position of a cut
+func (d *deck) recordAt(deckID uuid.UUID, action, pile, requester string, position int, cards []drawing.Card) {
+	draw := history.Draw{
+		ID:        uuid.New(),
+		DeckID:    deckID,
+		Number:    len(d.draws) + 1,
+		Action:    action,
+		Pile:      pile,
+		Position:  position,
+		Requester: requester,
+		DrawnAt:   time.Now().UTC(),
+		Cards:     make([]history.Card, 0, len(cards)),
+	}
+	for _, c := range cards {
+		draw.Cards = append(draw.Cards, history.Card{ID: c.ID, Code: c.Code, Value: c.Value, Suit: c.Suit, Copy: c.Copy, Reversed: c.Reversed})
+	}
+
+	d.draws = append(d.draws, draw)
+}
+
+// FindDraws returns draws from the deck with given ID in the order they took place, draws without cards have an empty
+// list of cards.
+func (r *Repository) FindDraws(deckID uuid.UUID) ([]history.Draw, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	d, ok := r.decks[deckID]
+	if !ok {
+		return []history.Draw{}, history.ErrNotFound
+	}
+
+	draws := make([]history.Draw, len(d.draws))
+	copy(draws, d.draws)
+
+	return draws, nil
+}
+
+// FindReveal returns commitment, salt and state of the deck with given ID along with every card of the deck in the
+// order the deck was created with.
+func (r *Repository) FindReveal(deckID uuid.UUID) (commitment.Reveal, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	d, ok := r.decks[deckID]
+	if !ok {
+		return commitment.Reveal{}, commitment.ErrNotFound
+	}
+
+	reveal := commitment.Reveal{
+		DeckID:     deckID,
+		Commitment: d.commitment,
+		Salt:       d.salt,
+		Closed:     d.closed,
+		Remaining:  d.remaining,
+	}
+	for _, c := range d.cards {
+		reveal.Cards = append(reveal.Cards, commitment.Card{ID: c.id, Code: c.code, Value: c.value, Suit: c.suit, Reversed: c.reversed})
+	}
+
+	return reveal, nil
+}
+
+// CloseDeck marks the deck with given ID as closed.
+func (r *Repository) CloseDeck(deckID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d, ok := r.decks[deckID]
+	if !ok {
+		return commitment.ErrNotFound
+	}
+
+	d.closed = true
+
+	return nil
+}
+
+// CreateDeckType stores a custom deck type and its cards in order
+func (r *Repository) CreateDeckType(t creating.DeckType) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.deckTypes[t.Name]; ok {
+		return creating.ErrDefinitionExists
+	}
+
+	r.deckTypes[t.Name] = copyDeckType(t)
+
+	return nil
+}
+
+// FindDeckType returns the custom deck type with given name and its cards in order
+func (r *Repository) FindDeckType(name string) (creating.DeckType, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	t, ok := r.deckTypes[name]
+	if !ok {
+		return creating.DeckType{}, creating.ErrDefinitionNotFound
+	}
+
+	return copyDeckType(t), nil
+}
+
+// copyDeckType returns a copy of t that shares nothing with it
+func copyDeckType(t creating.DeckType) creating.DeckType {
+	return creating.DeckType{
+		Name:  t.Name,
+		Suits: append([]creating.Symbol(nil), t.Suits...),
+		Ranks: append([]creating.Symbol(nil), t.Ranks...),
+		Cards: append([]creating.Card(nil), t.Cards...),
+	}
+}
+
+// availableCards returns cards that are not drawn, top of the deck first
+func (d *deck) availableCards() []drawing.Card {
+	return d.cardsBy(false)
+}
+
+// drawnCards returns drawn cards in position order, which keeps each pile in the order cards were put in it
+func (d *deck) drawnCards() []drawing.Card {
+	return d.cardsBy(true)
+}
+
+// cardsBy returns cards by their drawn status in position order
+func (d *deck) cardsBy(drawn bool) []drawing.Card {
+	var found []card
+	for _, c := range d.cards {
+		if drawn == c.drawn {
+			found = append(found, c)
+		}
+	}
+
+	// d.cards are in id order, which breaks ties
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].position < found[j].position
+	})
+
+	var cards []drawing.Card
+	for _, c := range found {
+		cards = append(cards, c.toDrawing())
+	}
+
+	return cards
+}
+
+func (c card) toDrawing() drawing.Card {
+	return drawing.Card{ID: c.id, Code: c.code, Value: c.value, Suit: c.suit, Copy: c.copy, Reversed: c.reversed, Pile: c.pile}
+}
+
+// toListing returns a drawing.Card as it is listed
+func toListing(c drawing.Card) listing.Card {
+	return listing.Card{ID: c.ID, Code: c.Code, Value: c.Value, Suit: c.Suit, Copy: c.Copy, Reversed: c.Reversed}
+}
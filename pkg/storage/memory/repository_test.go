@@ -0,0 +1,724 @@
+package memory_test
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/srgyrn/lucky-38/pkg/commitment"
+	"github.com/srgyrn/lucky-38/pkg/creating"
+	"github.com/srgyrn/lucky-38/pkg/drawing"
+	"github.com/srgyrn/lucky-38/pkg/history"
+	"github.com/srgyrn/lucky-38/pkg/listing"
+	"github.com/srgyrn/lucky-38/pkg/storage/memory"
+)
+
+func TestRepository_CreateDeck(t *testing.T) {
+	r := memory.NewRepository()
+
+	deck := creating.Deck{
+		Shuffled:  false,
+		Remaining: 3,
+		Cards: []creating.Card{
+			{Code: "AS", Value: "ACE", Suit: "SPADES"},
+			{Code: "2S", Value: "2", Suit: "SPADES"},
+			{Code: "3S", Value: "3", Suit: "SPADES"},
+		},
+	}
+	want := creating.Deck{
+		Shuffled:  false,
+		Remaining: 3,
+		Cards: []creating.Card{
+			{ID: 1, Code: "AS", Value: "ACE", Suit: "SPADES"},
+			{ID: 2, Code: "2S", Value: "2", Suit: "SPADES"},
+			{ID: 3, Code: "3S", Value: "3", Suit: "SPADES"},
+		},
+	}
+
+	err := r.CreateDeck(&deck)
+	if err != nil {
+		t.Errorf("CreateDeck() error = %v", err)
+		return
+	}
+	want.ID = deck.ID
+	if !reflect.DeepEqual(deck, want) {
+		t.Errorf("CreateDeck() got = %v, want %v", deck, want)
+	}
+}
+
+func TestRepository_Find(t *testing.T) {
+	t.Run("missing ID", func(t *testing.T) {
+		r := memory.NewRepository()
+
+		deckID, _ := uuid.Parse("69077400-88cd-11eb-8dcd-0242ac130003")
+		_, err := r.Find(deckID)
+		if !errors.Is(err, listing.ErrNotFound) {
+			t.Errorf("Find() want %T, got = %v", listing.ErrNotFound, err)
+		}
+	})
+
+	t.Run("valid find", func(t *testing.T) {
+		r := memory.NewRepository()
+		deckID := initDeck(t, r)
+
+		got, err := r.Find(deckID)
+		if err != nil {
+			t.Errorf("Find() error = %v", err)
+			return
+		}
+
+		want := listing.Deck{
+			ID:        deckID,
+			Shuffled:  false,
+			Remaining: 4,
+			Cards: []listing.Card{
+				{ID: 1, Code: "AS", Value: "ACE", Suit: "SPADES"},
+				{ID: 2, Code: "2S", Value: "2", Suit: "SPADES"},
+				{ID: 3, Code: "3S", Value: "3", Suit: "SPADES"},
+				{ID: 4, Code: "4S", Value: "4", Suit: "SPADES"},
+			},
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Find() got = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("seeded deck", func(t *testing.T) {
+		r := memory.NewRepository()
+
+		seed := int64(38)
+		deck := creating.Deck{Shuffled: true, Remaining: 1, Seed: &seed, Cards: []creating.Card{{Code: "AS", Value: "ACE", Suit: "SPADES"}}}
+		if err := r.CreateDeck(&deck); err != nil {
+			t.Fatalf("CreateDeck() error = %v", err)
+		}
+
+		got, err := r.Find(deck.ID)
+		if err != nil {
+			t.Fatalf("Find() error = %v", err)
+		}
+
+		if got.Seed == nil || seed != *got.Seed {
+			t.Errorf("Find() seed = %v, want %d", got.Seed, seed)
+		}
+	})
+}
+
+func TestRepository_DrawCards(t *testing.T) {
+	r := memory.NewRepository()
+	deckID := initDeck(t, r)
+
+	missingDeckID, _ := uuid.Parse("69077400-88cd-11eb-8dcd-0242ac130003")
+	_, err := r.DrawCards(missingDeckID, "", "test", func(available []drawing.Card) ([]drawing.Card, error) {
+		return available, nil
+	})
+	if !errors.Is(err, drawing.ErrNotFound) {
+		t.Errorf("DrawCards() want error = %v got %v", drawing.ErrNotFound, err)
+	}
+
+	got, err := r.DrawCards(deckID, "", "test", func(available []drawing.Card) ([]drawing.Card, error) {
+		return available[:2], nil
+	})
+	if err != nil {
+		t.Errorf("DrawCards() error = %v", err)
+		return
+	}
+
+	want := []drawing.Card{
+		{ID: 1, Value: "ACE", Suit: "SPADES", Code: "AS"},
+		{ID: 2, Value: "2", Suit: "SPADES", Code: "2S"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DrawCards() = %v, want %v", got, want)
+	}
+
+	deck, err := r.Find(deckID)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+
+	if deck.Remaining != 2 {
+		t.Errorf("deck remaining: %d, want: %d", deck.Remaining, 2)
+	}
+
+	if len(deck.Cards) != 2 {
+		t.Errorf("available card count %d, want %d", len(deck.Cards), 2)
+	}
+}
+
+func TestRepository_DrawCards_concurrent(t *testing.T) {
+	r := memory.NewRepository()
+	deck, err := creating.NewService(r).CreateDeck(creating.Deck{Remaining: creating.FrenchDeckCardTotal})
+	if err != nil {
+		t.Fatalf("CreateDeck() error = %v", err)
+	}
+
+	// more draws than the deck can serve, so some of them must fail
+	const draws = creating.FrenchDeckCardTotal/2 + 12
+	s := drawing.NewService(r)
+	results := make(chan []drawing.Card, draws)
+	var wg sync.WaitGroup
+	for i := 0; i < draws; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cards, _, err := s.Draw(deck.ID.String(), 2, "test")
+			if err != nil && !errors.Is(err, drawing.ErrNotFound) {
+				t.Errorf("Draw() error = %v", err)
+			}
+			results <- cards
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	dealt := make(map[int]int)
+	for cards := range results {
+		for _, c := range cards {
+			dealt[c.ID]++
+		}
+	}
+
+	if len(dealt) != creating.FrenchDeckCardTotal {
+		t.Errorf("dealt %d distinct cards, want %d", len(dealt), creating.FrenchDeckCardTotal)
+	}
+
+	for id, n := range dealt {
+		if n != 1 {
+			t.Errorf("card %d dealt %d times", id, n)
+		}
+	}
+
+	if got, _ := r.Find(deck.ID); got.Remaining != 0 {
+		t.Errorf("deck remaining: %d, want: 0", got.Remaining)
+	}
+}
+
+func TestRepository_FindAvailableCardByDeckID(t *testing.T) {
+	r := memory.NewRepository()
+	deckID := initDeck(t, r)
+
+	want := []drawing.Card{
+		{ID: 1, Value: "ACE", Suit: "SPADES", Code: "AS"},
+		{ID: 2, Value: "2", Suit: "SPADES", Code: "2S"},
+		{ID: 3, Value: "3", Suit: "SPADES", Code: "3S"},
+		{ID: 4, Value: "4", Suit: "SPADES", Code: "4S"},
+	}
+
+	missingDeckID, _ := uuid.Parse("69077400-88cd-11eb-8dcd-0242ac130003")
+	_, err := r.FindAvailableCardByDeckID(missingDeckID)
+	if !errors.Is(err, drawing.ErrNotFound) {
+		t.Errorf("FindAvailableCardByDeckID() want error = %v got %v", drawing.ErrNotFound, err)
+		return
+	}
+
+	got, err := r.FindAvailableCardByDeckID(deckID)
+	if err != nil {
+		t.Errorf("FindAvailableCardByDeckID() error = %v", err)
+		return
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAvailableCardByDeckID() = %v, want %v", got, want)
+	}
+
+	if _, err := r.DrawCards(deckID, "", "test", func(available []drawing.Card) ([]drawing.Card, error) {
+		return available, nil
+	}); err != nil {
+		t.Fatalf("DrawCards() error = %v", err)
+	}
+
+	if _, err := r.FindAvailableCardByDeckID(deckID); !errors.Is(err, drawing.ErrNotFound) {
+		t.Errorf("FindAvailableCardByDeckID() want error = %v got %v", drawing.ErrNotFound, err)
+	}
+}
+
+func TestRepository_FindDraws(t *testing.T) {
+	r := memory.NewRepository()
+	deckID := initDeck(t, r)
+
+	missingDeckID, _ := uuid.Parse("69077400-88cd-11eb-8dcd-0242ac130003")
+	if _, err := r.FindDraws(missingDeckID); !errors.Is(err, history.ErrNotFound) {
+		t.Errorf("FindDraws() want error = %v got %v", history.ErrNotFound, err)
+	}
+
+	s := drawing.NewService(r)
+	for _, requester := range []string{"dealer", "player1"} {
+		if _, _, err := s.Draw(deckID.String(), 2, requester); err != nil {
+			t.Fatalf("Draw() error = %v", err)
+		}
+	}
+
+	got, err := r.FindDraws(deckID)
+	if err != nil {
+		t.Fatalf("FindDraws() error = %v", err)
+	}
+
+	want := []history.Draw{
+		{
+			DeckID:    deckID,
+			Number:    1,
+			Action:    history.ActionDraw,
+			Requester: "dealer",
+			Cards: []history.Card{
+				{ID: 1, Code: "AS", Value: "ACE", Suit: "SPADES"},
+				{ID: 2, Code: "2S", Value: "2", Suit: "SPADES"},
+			},
+		},
+		{
+			DeckID:    deckID,
+			Number:    2,
+			Action:    history.ActionDraw,
+			Requester: "player1",
+			Cards: []history.Card{
+				{ID: 3, Code: "3S", Value: "3", Suit: "SPADES"},
+				{ID: 4, Code: "4S", Value: "4", Suit: "SPADES"},
+			},
+		},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("FindDraws() = %v, want %v", got, want)
+	}
+
+	for i := range got {
+		want[i].ID, want[i].DrawnAt = got[i].ID, got[i].DrawnAt
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindDraws() = %v, want %v", got, want)
+	}
+}
+
+func initDeck(t *testing.T, r *memory.Repository) uuid.UUID {
+	t.Helper()
+	deck := creating.Deck{
+		Shuffled:  false,
+		Remaining: 4,
+		Cards: []creating.Card{
+			{Code: "AS", Value: "ACE", Suit: "SPADES"},
+			{Code: "2S", Value: "2", Suit: "SPADES"},
+			{Code: "3S", Value: "3", Suit: "SPADES"},
+			{Code: "4S", Value: "4", Suit: "SPADES"},
+		},
+	}
+
+	if err := r.CreateDeck(&deck); err != nil {
+		t.Fatalf("CreateDeck() error = %v", err)
+	}
+
+	return deck.ID
+}
+
+func TestRepository_ReturnCards(t *testing.T) {
+	r := memory.NewRepository()
+	deckID := initDeck(t, r)
+
+	s := drawing.NewService(r)
+	if _, _, err := s.Draw(deckID.String(), 2, "dealer"); err != nil {
+		t.Fatalf("Draw() error = %v", err)
+	}
+
+	got, err := s.Return(deckID.String(), []string{"2S"}, false, "dealer")
+	if err != nil {
+		t.Fatalf("Return() error = %v", err)
+	}
+
+	want := []drawing.Card{{ID: 2, Value: "2", Suit: "SPADES", Code: "2S", Rank: 2, Color: "BLACK"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Return() = %v, want %v", got, want)
+	}
+
+	deck, err := r.Find(deckID)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+
+	if deck.Remaining != 3 {
+		t.Errorf("deck remaining: %d, want: 3", deck.Remaining)
+	}
+
+	available, err := r.FindAvailableCardByDeckID(deckID)
+	if err != nil {
+		t.Fatalf("FindAvailableCardByDeckID() error = %v", err)
+	}
+
+	var codes []string
+	for _, c := range available {
+		codes = append(codes, c.Code)
+	}
+
+	if wantCodes := []string{"3S", "4S", "2S"}; !reflect.DeepEqual(codes, wantCodes) {
+		t.Errorf("available cards %v, want %v", codes, wantCodes)
+	}
+
+	if _, err := s.Return(deckID.String(), []string{"2S"}, false, "dealer"); !errors.Is(err, drawing.ErrCardNotDrawn) {
+		t.Errorf("Return() error = %v, want %v", err, drawing.ErrCardNotDrawn)
+	}
+
+	if _, err := s.Return(deckID.String(), nil, true, "dealer"); err != nil {
+		t.Fatalf("Return() error = %v", err)
+	}
+
+	if deck, _ := r.Find(deckID); deck.Remaining != 4 || len(deck.Cards) != 4 {
+		t.Errorf("deck remaining: %d with %d cards, want 4", deck.Remaining, len(deck.Cards))
+	}
+
+	draws, err := r.FindDraws(deckID)
+	if err != nil {
+		t.Fatalf("FindDraws() error = %v", err)
+	}
+
+	var actions []string
+	for _, d := range draws {
+		actions = append(actions, d.Action)
+	}
+
+	if wantActions := []string{history.ActionDraw, history.ActionReturn, history.ActionReturn, history.ActionShuffle}; !reflect.DeepEqual(actions, wantActions) {
+		t.Errorf("history actions %v, want %v", actions, wantActions)
+	}
+}
+
+func TestRepository_ReturnCards_failingArrange(t *testing.T) {
+	r := memory.NewRepository()
+	deckID := initDeck(t, r)
+
+	s := drawing.NewService(r)
+	if _, _, err := s.Draw(deckID.String(), 2, "dealer"); err != nil {
+		t.Fatalf("Draw() error = %v", err)
+	}
+
+	_, err := r.ReturnCards(deckID, "", "dealer", true, func(drawn []drawing.Card) ([]drawing.Card, error) {
+		return drawn, nil
+	}, func(available []drawing.Card) ([]drawing.Card, error) {
+		return nil, errors.New("test error")
+	})
+	if err == nil {
+		t.Fatal("ReturnCards() want error")
+	}
+
+	deck, err := r.Find(deckID)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+
+	draws, err := r.FindDraws(deckID)
+	if err != nil {
+		t.Fatalf("FindDraws() error = %v", err)
+	}
+
+	if 2 != deck.Remaining || 2 != len(deck.Cards) || 1 != len(draws) {
+		t.Errorf("deck remaining: %d with %d cards and %d draws, want 2 with 2 cards and 1 draw", deck.Remaining, len(deck.Cards), len(draws))
+	}
+}
+
+func TestRepository_FindReveal(t *testing.T) {
+	r := memory.NewRepository()
+
+	missingDeckID, _ := uuid.Parse("69077400-88cd-11eb-8dcd-0242ac130003")
+	if _, err := r.FindReveal(missingDeckID); !errors.Is(err, commitment.ErrNotFound) {
+		t.Errorf("FindReveal() want error = %v got %v", commitment.ErrNotFound, err)
+	}
+
+	if err := r.CloseDeck(missingDeckID); !errors.Is(err, commitment.ErrNotFound) {
+		t.Errorf("CloseDeck() want error = %v got %v", commitment.ErrNotFound, err)
+	}
+
+	deck, err := creating.NewService(r).CreateDeck(creating.Deck{
+		Shuffled:  true,
+		Remaining: 4,
+		Cards:     []creating.Card{{Code: "AS"}, {Code: "2S"}, {Code: "3S"}, {Code: "4S"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateDeck() error = %v", err)
+	}
+
+	// reshuffling changes positions of the cards, reveal keeps the initial order
+	if _, err := drawing.NewService(r).Return(deck.ID.String(), nil, true, "dealer"); err != nil {
+		t.Fatalf("Return() error = %v", err)
+	}
+
+	if err := r.CloseDeck(deck.ID); err != nil {
+		t.Fatalf("CloseDeck() error = %v", err)
+	}
+
+	got, err := r.FindReveal(deck.ID)
+	if err != nil {
+		t.Fatalf("FindReveal() error = %v", err)
+	}
+
+	if !got.Closed || deck.Commitment != got.Commitment || !commitment.Verify(got.Commitment, got.Salt, got.Entries()) {
+		t.Errorf("FindReveal() = %v, want closed deck matching the commitment %s", got, deck.Commitment)
+	}
+
+	if _, _, err := drawing.NewService(r).Draw(deck.ID.String(), 1, "dealer"); !errors.Is(err, drawing.ErrDeckClosed) {
+		t.Errorf("Draw() error = %v, want %v", err, drawing.ErrDeckClosed)
+	}
+
+	if _, err := drawing.NewService(r).Return(deck.ID.String(), nil, false, "dealer"); !errors.Is(err, drawing.ErrDeckClosed) {
+		t.Errorf("Return() error = %v, want %v", err, drawing.ErrDeckClosed)
+	}
+}
+
+func TestRepository_shoe(t *testing.T) {
+	r := memory.NewRepository()
+
+	deck, err := creating.NewService(r).CreateDeck(creating.Deck{Remaining: creating.FrenchDeckCardTotal, Decks: 2})
+	if err != nil {
+		t.Fatalf("CreateDeck() error = %v", err)
+	}
+
+	cards, _, err := drawing.NewService(r).Draw(deck.ID.String(), creating.FrenchDeckCardTotal+1, "dealer")
+	if err != nil {
+		t.Fatalf("Draw() error = %v", err)
+	}
+
+	first, last := cards[0], cards[len(cards)-1]
+	if first.Code != last.Code || 1 != first.Copy || 2 != last.Copy {
+		t.Errorf("Draw() first %v and last %v, want the same card from both decks", first, last)
+	}
+
+	draws, err := r.FindDraws(deck.ID)
+	if err != nil {
+		t.Fatalf("FindDraws() error = %v", err)
+	}
+
+	if got := draws[0].Cards; 1 != got[0].Copy || 2 != got[len(got)-1].Copy {
+		t.Errorf("FindDraws() cards %v, want copies of both decks", got)
+	}
+
+	listed, err := r.Find(deck.ID)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+
+	if 2 != listed.Cards[0].Copy {
+		t.Errorf("Find() first card %v, want it from the second deck", listed.Cards[0])
+	}
+}
+
+func TestRepository_DeckType(t *testing.T) {
+	r := memory.NewRepository()
+	s := creating.NewService(r)
+
+	if _, err := r.FindDeckType("colors"); !errors.Is(err, creating.ErrDefinitionNotFound) {
+		t.Errorf("FindDeckType() error = %v, want %v", err, creating.ErrDefinitionNotFound)
+	}
+
+	def := creating.Definition{
+		Name:  "colors",
+		Suits: []creating.Symbol{{Code: "R", Name: "RED"}},
+		Ranks: []creating.Symbol{{Code: "1", Name: "ONE"}, {Code: "2", Name: "TWO"}},
+	}
+	want, err := s.CreateDefinition(def)
+	if err != nil {
+		t.Fatalf("CreateDefinition() error = %v", err)
+	}
+
+	if _, err := s.CreateDefinition(def); !errors.Is(err, creating.ErrDefinitionExists) {
+		t.Errorf("CreateDefinition() error = %v, want %v", err, creating.ErrDefinitionExists)
+	}
+
+	if got, err := r.FindDeckType("colors"); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("FindDeckType() got = %v, error = %v, want %v", got, err, want)
+	}
+
+	deck, err := s.CreateDeck(creating.Deck{Type: "colors", Remaining: 1, Cards: []creating.Card{{Code: "2R"}}})
+	if err != nil {
+		t.Fatalf("CreateDeck() error = %v", err)
+	}
+
+	if c := deck.Cards[0]; "TWO" != c.Value || "RED" != c.Suit {
+		t.Errorf("CreateDeck() card %v, want TWO of RED", c)
+	}
+}
+
+func TestRepository_piles(t *testing.T) {
+	r := memory.NewRepository()
+	deckID := initDeck(t, r)
+
+	s := drawing.NewService(r)
+	if _, _, err := s.DrawInto(deckID.String(), "player1", 2, "dealer"); err != nil {
+		t.Fatalf("DrawInto() error = %v", err)
+	}
+
+	if _, _, err := s.DrawInto(deckID.String(), "dealer", 1, "dealer"); err != nil {
+		t.Fatalf("DrawInto() error = %v", err)
+	}
+
+	got, err := s.Move(deckID.String(), "player1", "dealer", []string{"2S"}, "dealer")
+	if err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+
+	want := []drawing.Card{{ID: 2, Value: "2", Suit: "SPADES", Code: "2S", Rank: 2, Color: "BLACK", Pile: "dealer"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Move() = %v, want %v", got, want)
+	}
+
+	pile, err := r.FindPile(deckID, "dealer")
+	if err != nil {
+		t.Fatalf("FindPile() error = %v", err)
+	}
+
+	wantPile := []listing.Card{
+		{ID: 3, Code: "3S", Value: "3", Suit: "SPADES"},
+		{ID: 2, Code: "2S", Value: "2", Suit: "SPADES"},
+	}
+	if !reflect.DeepEqual(pile, wantPile) {
+		t.Errorf("FindPile() = %v, want %v", pile, wantPile)
+	}
+
+	if _, err := s.ReturnPile(deckID.String(), "dealer", false, "dealer"); err != nil {
+		t.Fatalf("ReturnPile() error = %v", err)
+	}
+
+	deck, err := r.Find(deckID)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+
+	wantPiles := listing.Piles{"player1": 1}
+	if !reflect.DeepEqual(deck.Piles, wantPiles) || 3 != deck.Remaining || "2S" != deck.Cards[2].Code {
+		t.Errorf("Find() = %v, want piles %v and 2S at the bottom", deck, wantPiles)
+	}
+
+	if _, err := r.FindPile(uuid.New(), "dealer"); !errors.Is(err, listing.ErrNotFound) {
+		t.Errorf("FindPile() want error = %v got %v", listing.ErrNotFound, err)
+	}
+
+	draws, err := r.FindDraws(deckID)
+	if err != nil {
+		t.Fatalf("FindDraws() error = %v", err)
+	}
+
+	if 4 != len(draws) || history.ActionMove != draws[2].Action || "dealer" != draws[2].Pile || "dealer" != draws[3].Pile {
+		t.Errorf("FindDraws() = %v, want draws, a move and a return from dealer", draws)
+	}
+}
+
+func TestRepository_DealCards(t *testing.T) {
+	r := memory.NewRepository()
+	deckID := initDeck(t, r)
+	s := drawing.NewService(r)
+
+	if _, _, err := s.Deal(deckID.String(), drawing.Seats(3), 2, "dealer"); !errors.Is(err, drawing.ErrInsufficientRemainingCard) {
+		t.Fatalf("Deal() want error = %v got %v", drawing.ErrInsufficientRemainingCard, err)
+	}
+
+	got, _, err := s.Deal(deckID.String(), drawing.Seats(2), 2, "dealer")
+	if err != nil {
+		t.Fatalf("Deal() error = %v", err)
+	}
+
+	want := []drawing.Hand{
+		{Seat: "player1", Cards: []drawing.Card{
+			{ID: 1, Value: "ACE", Suit: "SPADES", Code: "AS", Rank: 1, Color: "BLACK", Pile: "player1"},
+			{ID: 3, Value: "3", Suit: "SPADES", Code: "3S", Rank: 3, Color: "BLACK", Pile: "player1"},
+		}},
+		{Seat: "player2", Cards: []drawing.Card{
+			{ID: 2, Value: "2", Suit: "SPADES", Code: "2S", Rank: 2, Color: "BLACK", Pile: "player2"},
+			{ID: 4, Value: "4", Suit: "SPADES", Code: "4S", Rank: 4, Color: "BLACK", Pile: "player2"},
+		}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Deal() = %v, want %v", got, want)
+	}
+
+	deck, err := r.Find(deckID)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+
+	wantPiles := listing.Piles{"player1": 2, "player2": 2}
+	if 0 != deck.Remaining || !reflect.DeepEqual(deck.Piles, wantPiles) {
+		t.Errorf("Find() remaining %d with piles %v, want 0 with %v", deck.Remaining, deck.Piles, wantPiles)
+	}
+
+	draws, err := r.FindDraws(deckID)
+	if err != nil {
+		t.Fatalf("FindDraws() error = %v", err)
+	}
+
+	if 2 != len(draws) || history.ActionDeal != draws[1].Action || "player2" != draws[1].Pile {
+		t.Errorf("FindDraws() = %v, want a deal for each player", draws)
+	}
+}
+
+func TestRepository_peekAndBurn(t *testing.T) {
+	r := memory.NewRepository()
+	deckID := initDeck(t, r)
+	s := drawing.NewService(r)
+
+	peeked, err := s.Peek(deckID.String(), 1, "dealer")
+	if err != nil || 1 != len(peeked) || "AS" != peeked[0].Code {
+		t.Fatalf("Peek() = %v, %v, want AS", peeked, err)
+	}
+
+	if burned, _, err := s.Burn(deckID.String(), 2, "dealer"); err != nil || 2 != burned {
+		t.Fatalf("Burn() = %d, %v, want 2", burned, err)
+	}
+
+	deck, err := r.Find(deckID)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+
+	wantPiles := listing.Piles{drawing.BurnPile: 2}
+	if 2 != deck.Remaining || "3S" != deck.Cards[0].Code || !reflect.DeepEqual(deck.Piles, wantPiles) {
+		t.Errorf("Find() = %v, want 3S on top and piles %v", deck, wantPiles)
+	}
+
+	draws, err := r.FindDraws(deckID)
+	if err != nil {
+		t.Fatalf("FindDraws() error = %v", err)
+	}
+
+	if 2 != len(draws) || history.ActionPeek != draws[0].Action || history.ActionBurn != draws[1].Action {
+		t.Errorf("FindDraws() = %v, want a peek and a burn", draws)
+	}
+}
+
+func TestRepository_cut(t *testing.T) {
+	r := memory.NewRepository()
+	deck, err := creating.NewService(r).CreateDeck(creating.Deck{
+		Remaining:   4,
+		Penetration: 50,
+		Cards:       []creating.Card{{Code: "AS"}, {Code: "2S"}, {Code: "3S"}, {Code: "4S"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateDeck() error = %v", err)
+	}
+
+	s := drawing.NewService(r)
+	if _, err := s.Cut(deck.ID.String(), 3, "dealer"); err != nil {
+		t.Fatalf("Cut() error = %v", err)
+	}
+
+	found, err := r.Find(deck.ID)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+
+	if 2 != found.CutCard || 4 != len(found.Cards) || "4S" != found.Cards[0].Code || "3S" != found.Cards[3].Code {
+		t.Errorf("Find() cut card %d with cards %v, want 2 with 4S on top and 3S at the bottom", found.CutCard, found.Cards)
+	}
+
+	for i, want := range []bool{false, true} {
+		if _, reached, err := s.Draw(deck.ID.String(), 1, "dealer"); err != nil || want != reached {
+			t.Errorf("Draw() %d reached = %v, error = %v, want %v", i+1, reached, err, want)
+		}
+	}
+
+	draws, err := r.FindDraws(deck.ID)
+	if err != nil {
+		t.Fatalf("FindDraws() error = %v", err)
+	}
+
+	if 3 != len(draws) || history.ActionCut != draws[0].Action || 3 != draws[0].Position || !reflect.DeepEqual(draws[0].Cards, []history.Card{}) {
+		t.Errorf("FindDraws() = %v, want a cut at 3 without cards and 2 draws", draws)
+	}
+}
@@ -0,0 +1,145 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/srgyrn/lucky-38/pkg/config"
+)
+
+// ErrUnknownVersion is returned when the database is at a schema version that this build does not know about
+var ErrUnknownVersion = errors.New("database schema is newer than the application")
+
+type (
+	migration struct {
+		version     int
+		description string
+		up          script
+		down        script
+	}
+
+	// script holds statements of a migration step. SQL is run on every driver, unless a driver has its own statements.
+	script struct {
+		SQL    string
+		SQLite string
+	}
+)
+
+// forDriver returns the statements to run on given driver
+func (s script) forDriver(driver string) string {
+	if config.DriverSQLite == driver && "" != s.SQLite {
+		return s.SQLite
+	}
+
+	return s.SQL
+}
+
+const createMigrationTable = `
+CREATE TABLE IF NOT EXISTS schema_migrations
+(
+    version     INTEGER PRIMARY KEY,
+    description VARCHAR(255) NOT NULL,
+    applied_at  TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`
+
+// MigrateUp applies every pending migration in order and returns the resulting schema version.
+// Each migration runs in its own transaction, so a failing migration leaves the schema at the previous version.
+func (r *Repository) MigrateUp() (int, error) {
+	current, err := r.SchemaVersion()
+	if err != nil {
+		return current, err
+	}
+
+	for _, m := range sortedMigrations() {
+		if m.version <= current {
+			continue
+		}
+
+		if err := r.applyMigration(m.up.forDriver(r.driver), "INSERT INTO schema_migrations (version, description) VALUES ($1, $2)", m.version, m.description); err != nil {
+			return current, fmt.Errorf("migration %d (%s) failed: %v", m.version, m.description, err)
+		}
+		current = m.version
+	}
+
+	return current, nil
+}
+
+// MigrateDown reverts the last n applied migrations and returns the resulting schema version.
+func (r *Repository) MigrateDown(n int) (int, error) {
+	current, err := r.SchemaVersion()
+	if err != nil {
+		return current, err
+	}
+
+	applied := sortedMigrations()
+	for i := len(applied) - 1; i >= 0 && n > 0; i-- {
+		m := applied[i]
+		if m.version > current {
+			continue
+		}
+
+		if err := r.applyMigration(m.down.forDriver(r.driver), "DELETE FROM schema_migrations WHERE version = $1", m.version); err != nil {
+			return current, fmt.Errorf("reverting migration %d (%s) failed: %v", m.version, m.description, err)
+		}
+
+		current = 0
+		if i > 0 {
+			current = applied[i-1].version
+		}
+		n--
+	}
+
+	return current, nil
+}
+
+// SchemaVersion returns the version of the last applied migration, 0 if none is applied yet.
+func (r *Repository) SchemaVersion() (int, error) {
+	if _, err := r.db.Exec(createMigrationTable); err != nil {
+		return 0, fmt.Errorf("could not create schema_migrations: %v", err)
+	}
+
+	var version sql.NullInt64
+	if err := r.db.QueryRow("SELECT MAX(version) FROM schema_migrations").Scan(&version); err != nil {
+		return 0, err
+	}
+
+	if latest := sortedMigrations(); 0 < len(latest) && int(version.Int64) > latest[len(latest)-1].version {
+		return int(version.Int64), ErrUnknownVersion
+	}
+
+	return int(version.Int64), nil
+}
+
+// applyMigration runs statements and records the change in schema_migrations within a single transaction
+func (r *Repository) applyMigration(statements, record string, args ...interface{}) error {
+	ctx := context.Background()
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error at creating transaction: %v", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, statements); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func sortedMigrations() []migration {
+	sorted := make([]migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].version < sorted[j].version
+	})
+
+	return sorted
+}
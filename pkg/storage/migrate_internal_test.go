@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestRepository_Migrate(t *testing.T) {
+	r := getRepository(t)
+	latest := migrations[len(migrations)-1].version
+
+	got, err := r.SchemaVersion()
+	if err != nil {
+		t.Fatalf("SchemaVersion() err: %v", err)
+	}
+
+	if got != latest {
+		t.Fatalf("SchemaVersion() = %d, want %d", got, latest)
+	}
+
+	t.Run("up is idempotent", func(t *testing.T) {
+		got, err := r.MigrateUp()
+		if err != nil {
+			t.Fatalf("MigrateUp() err: %v", err)
+		}
+
+		if got != latest {
+			t.Errorf("MigrateUp() = %d, want %d", got, latest)
+		}
+	})
+
+	t.Run("down then up", func(t *testing.T) {
+		got, err := r.MigrateDown(len(migrations))
+		if err != nil {
+			t.Fatalf("MigrateDown() err: %v", err)
+		}
+
+		if got != 0 {
+			t.Errorf("MigrateDown() = %d, want 0", got)
+		}
+
+		if _, err := r.db.Exec("SELECT 1 FROM decks"); err == nil {
+			t.Errorf("decks table exists after reverting every migration")
+		}
+
+		got, err = r.MigrateUp()
+		if err != nil {
+			t.Fatalf("MigrateUp() err: %v", err)
+		}
+
+		if got != latest {
+			t.Errorf("MigrateUp() = %d, want %d", got, latest)
+		}
+
+		if 0 != r.TestCountDecks(t) {
+			t.Errorf("decks table is not empty after migrating up")
+		}
+	})
+
+	t.Run("positions keep deal order", func(t *testing.T) {
+		// revert down to the migration before positions were added
+		if _, err := r.MigrateDown(latest - 3); err != nil {
+			t.Fatalf("MigrateDown() err: %v", err)
+		}
+
+		deckID := uuid.New()
+		if _, err := r.db.Exec("INSERT INTO decks (deck_id, shuffled, remaining) VALUES ($1, $2, $3)", deckID, false, 3); err != nil {
+			t.Fatalf("inserting deck err: %v", err)
+		}
+		defer r.TestTeardown(t)
+
+		for _, code := range []string{"AS", "2S", "3S"} {
+			if _, err := r.db.Exec("INSERT INTO cards (code, value, suit, drawn, deck) VALUES ($1, $2, $3, $4, $5)", code, code[:1], "SPADES", false, deckID); err != nil {
+				t.Fatalf("inserting card err: %v", err)
+			}
+		}
+
+		if _, err := r.MigrateUp(); err != nil {
+			t.Fatalf("MigrateUp() err: %v", err)
+		}
+
+		cards, err := r.findCards(r.db, deckID, false)
+		if err != nil {
+			t.Fatalf("findCards() err: %v", err)
+		}
+
+		var codes []string
+		for _, c := range cards {
+			codes = append(codes, c.Code)
+		}
+
+		// cards used to be dealt last inserted first
+		if want := []string{"3S", "2S", "AS"}; !reflect.DeepEqual(codes, want) {
+			t.Errorf("available cards %v, want %v", codes, want)
+		}
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		if _, err := r.db.Exec("INSERT INTO schema_migrations (version, description) VALUES ($1, $2)", latest+1, "from the future"); err != nil {
+			t.Fatalf("inserting version err: %v", err)
+		}
+		defer r.db.Exec("DELETE FROM schema_migrations WHERE version = $1", latest+1)
+
+		if _, err := r.MigrateUp(); !errors.Is(err, ErrUnknownVersion) {
+			t.Errorf("MigrateUp() err = %v, want %v", err, ErrUnknownVersion)
+		}
+	})
+}
@@ -0,0 +1,289 @@
+package storage
+
+// migrations holds every schema change in the order they are applied. Never edit a released migration,
+// append a new one instead.
+var migrations = []migration{
+	{
+		version:     1,
+		description: "create decks and cards",
+		up: script{
+			SQL: `
+CREATE TABLE IF NOT EXISTS decks
+(
+    deck_id   UUID PRIMARY KEY,
+    shuffled  BOOLEAN NOT NULL DEFAULT false,
+    remaining INTEGER NOT NULL DEFAULT 52
+);
+
+CREATE TABLE IF NOT EXISTS cards
+(
+    card_id SERIAL PRIMARY KEY,
+    code    VARCHAR(3)  NOT NULL,
+    value   VARCHAR(10) NOT NULL,
+    suit    VARCHAR(10) NOT NULL,
+    drawn   BOOLEAN     NOT NULL DEFAULT false,
+    deck    UUID        NOT NULL,
+
+    CONSTRAINT fk_card_deck
+        FOREIGN KEY (deck)
+            REFERENCES decks (deck_id)
+            ON DELETE CASCADE
+            DEFERRABLE INITIALLY DEFERRED
+);`,
+			SQLite: `
+CREATE TABLE IF NOT EXISTS decks
+(
+    deck_id   TEXT PRIMARY KEY,
+    shuffled  BOOLEAN NOT NULL DEFAULT false,
+    remaining INTEGER NOT NULL DEFAULT 52
+);
+
+CREATE TABLE IF NOT EXISTS cards
+(
+    card_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    code    VARCHAR(3)  NOT NULL,
+    value   VARCHAR(10) NOT NULL,
+    suit    VARCHAR(10) NOT NULL,
+    drawn   BOOLEAN     NOT NULL DEFAULT false,
+    deck    TEXT        NOT NULL,
+
+    CONSTRAINT fk_card_deck
+        FOREIGN KEY (deck)
+            REFERENCES decks (deck_id)
+            ON DELETE CASCADE
+            DEFERRABLE INITIALLY DEFERRED
+);`,
+		},
+		down: script{SQL: `
+DROP TABLE cards;
+DROP TABLE decks;`},
+	},
+	{
+		version:     2,
+		description: "create draws and draw_cards",
+		up: script{
+			SQL: `
+CREATE TABLE draws
+(
+    draw_id   UUID         PRIMARY KEY,
+    deck      UUID         NOT NULL,
+    number    INTEGER      NOT NULL,
+    requester VARCHAR(255) NOT NULL DEFAULT '',
+    drawn_at  TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uq_draw_deck_number UNIQUE (deck, number),
+    CONSTRAINT fk_draw_deck
+        FOREIGN KEY (deck)
+            REFERENCES decks (deck_id)
+            ON DELETE CASCADE
+);
+
+CREATE TABLE draw_cards
+(
+    draw_id UUID        NOT NULL,
+    seq     INTEGER     NOT NULL,
+    card_id INTEGER     NOT NULL,
+    code    VARCHAR(3)  NOT NULL,
+    value   VARCHAR(10) NOT NULL,
+    suit    VARCHAR(10) NOT NULL,
+
+    PRIMARY KEY (draw_id, seq),
+    CONSTRAINT fk_draw_card_draw
+        FOREIGN KEY (draw_id)
+            REFERENCES draws (draw_id)
+            ON DELETE CASCADE
+);`,
+			SQLite: `
+CREATE TABLE draws
+(
+    draw_id   TEXT         PRIMARY KEY,
+    deck      TEXT         NOT NULL,
+    number    INTEGER      NOT NULL,
+    requester VARCHAR(255) NOT NULL DEFAULT '',
+    drawn_at  TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uq_draw_deck_number UNIQUE (deck, number),
+    CONSTRAINT fk_draw_deck
+        FOREIGN KEY (deck)
+            REFERENCES decks (deck_id)
+            ON DELETE CASCADE
+);
+
+CREATE TABLE draw_cards
+(
+    draw_id TEXT        NOT NULL,
+    seq     INTEGER     NOT NULL,
+    card_id INTEGER     NOT NULL,
+    code    VARCHAR(3)  NOT NULL,
+    value   VARCHAR(10) NOT NULL,
+    suit    VARCHAR(10) NOT NULL,
+
+    PRIMARY KEY (draw_id, seq),
+    CONSTRAINT fk_draw_card_draw
+        FOREIGN KEY (draw_id)
+            REFERENCES draws (draw_id)
+            ON DELETE CASCADE
+);`,
+		},
+		down: script{SQL: `
+DROP TABLE draw_cards;
+DROP TABLE draws;`},
+	},
+	{
+		version:     3,
+		description: "add action to draws",
+		up: script{SQL: `
+ALTER TABLE draws ADD COLUMN action VARCHAR(10) NOT NULL DEFAULT 'draw';`},
+		down: script{SQL: `
+ALTER TABLE draws DROP COLUMN action;`},
+	},
+	{
+		version:     4,
+		description: "add position to cards",
+		// existing decks keep dealing from the highest card_id
+		up: script{SQL: `
+ALTER TABLE cards ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+UPDATE cards SET position = (SELECT COUNT(*) FROM cards c WHERE c.deck = cards.deck AND c.card_id > cards.card_id);
+CREATE INDEX idx_cards_deck_position ON cards (deck, position);`},
+		down: script{SQL: `
+DROP INDEX idx_cards_deck_position;
+ALTER TABLE cards DROP COLUMN position;`},
+	},
+	{
+		version:     5,
+		description: "add seed to decks",
+		up: script{SQL: `
+ALTER TABLE decks ADD COLUMN seed BIGINT;`},
+		down: script{SQL: `
+ALTER TABLE decks DROP COLUMN seed;`},
+	},
+	{
+		version:     6,
+		description: "add commitment to decks",
+		up: script{SQL: `
+ALTER TABLE decks ADD COLUMN commitment VARCHAR(64);
+ALTER TABLE decks ADD COLUMN salt VARCHAR(64);
+ALTER TABLE decks ADD COLUMN closed BOOLEAN NOT NULL DEFAULT false;`},
+		down: script{SQL: `
+ALTER TABLE decks DROP COLUMN closed;
+ALTER TABLE decks DROP COLUMN salt;
+ALTER TABLE decks DROP COLUMN commitment;`},
+	},
+	{
+		version:     7,
+		description: "add copy to cards",
+		up: script{SQL: `
+ALTER TABLE cards ADD COLUMN copy INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE draw_cards ADD COLUMN copy INTEGER NOT NULL DEFAULT 0;`},
+		down: script{SQL: `
+ALTER TABLE draw_cards DROP COLUMN copy;
+ALTER TABLE cards DROP COLUMN copy;`},
+	},
+	{
+		version:     8,
+		description: "create deck types",
+		up: script{SQL: `
+CREATE TABLE IF NOT EXISTS deck_types
+(
+    name VARCHAR(40) PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS deck_type_cards
+(
+    deck_type VARCHAR(40) NOT NULL,
+    position  INTEGER     NOT NULL,
+    code      VARCHAR(3)  NOT NULL,
+    value     VARCHAR(10) NOT NULL,
+    suit      VARCHAR(10) NOT NULL,
+
+    PRIMARY KEY (deck_type, position),
+    CONSTRAINT fk_deck_type_card_type
+        FOREIGN KEY (deck_type)
+            REFERENCES deck_types (name)
+            ON DELETE CASCADE
+);`},
+		down: script{SQL: `
+DROP TABLE deck_type_cards;
+DROP TABLE deck_types;`},
+	},
+	{
+		version:     9,
+		description: "add reversed to cards, widen card values",
+		// SQLite does not enforce lengths of VARCHAR columns
+		up: script{
+			SQL: `
+ALTER TABLE cards ALTER COLUMN value TYPE VARCHAR(30);
+ALTER TABLE draw_cards ALTER COLUMN value TYPE VARCHAR(30);
+ALTER TABLE cards ADD COLUMN reversed BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE draw_cards ADD COLUMN reversed BOOLEAN NOT NULL DEFAULT false;`,
+			SQLite: `
+ALTER TABLE cards ADD COLUMN reversed BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE draw_cards ADD COLUMN reversed BOOLEAN NOT NULL DEFAULT false;`,
+		},
+		down: script{
+			SQL: `
+ALTER TABLE draw_cards DROP COLUMN reversed;
+ALTER TABLE cards DROP COLUMN reversed;
+ALTER TABLE draw_cards ALTER COLUMN value TYPE VARCHAR(10);
+ALTER TABLE cards ALTER COLUMN value TYPE VARCHAR(10);`,
+			SQLite: `
+ALTER TABLE draw_cards DROP COLUMN reversed;
+ALTER TABLE cards DROP COLUMN reversed;`,
+		},
+	},
+	{
+		version:     10,
+		description: "create deck type symbols",
+		up: script{SQL: `
+CREATE TABLE IF NOT EXISTS deck_type_symbols
+(
+    deck_type VARCHAR(40) NOT NULL,
+    kind      VARCHAR(4)  NOT NULL,
+    position  INTEGER     NOT NULL,
+    code      VARCHAR(3)  NOT NULL,
+    name      VARCHAR(10) NOT NULL,
+
+    PRIMARY KEY (deck_type, kind, position),
+    CONSTRAINT fk_deck_type_symbol_type
+        FOREIGN KEY (deck_type)
+            REFERENCES deck_types (name)
+            ON DELETE CASCADE
+);`},
+		down: script{SQL: `
+DROP TABLE deck_type_symbols;`},
+	},
+	{
+		version:     11,
+		description: "add piles to cards and draws",
+		up: script{SQL: `
+ALTER TABLE cards ADD COLUMN pile VARCHAR(40) NOT NULL DEFAULT '';
+ALTER TABLE draws ADD COLUMN pile VARCHAR(40) NOT NULL DEFAULT '';`},
+		down: script{SQL: `
+ALTER TABLE draws DROP COLUMN pile;
+ALTER TABLE cards DROP COLUMN pile;`},
+	},
+	{
+		version:     12,
+		description: "add cut card to decks",
+		up: script{SQL: `
+ALTER TABLE decks ADD COLUMN cut_card INTEGER NOT NULL DEFAULT 0;`},
+		down: script{SQL: `
+ALTER TABLE decks DROP COLUMN cut_card;`},
+	},
+	{
+		version:     13,
+		description: "add copy to deck type cards",
+		up: script{SQL: `
+ALTER TABLE deck_type_cards ADD COLUMN copy INTEGER NOT NULL DEFAULT 0;`},
+		down: script{SQL: `
+ALTER TABLE deck_type_cards DROP COLUMN copy;`},
+	},
+	{
+		version:     14,
+		description: "add position to draws",
+		up: script{SQL: `
+ALTER TABLE draws ADD COLUMN position INTEGER NOT NULL DEFAULT 0;`},
+		down: script{SQL: `
+ALTER TABLE draws DROP COLUMN position;`},
+	},
+}
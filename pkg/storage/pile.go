@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/srgyrn/lucky-38/pkg/drawing"
+	"github.com/srgyrn/lucky-38/pkg/history"
+	"github.com/srgyrn/lucky-38/pkg/listing"
+)
+
+// FindPile returns cards in the pile with given name of the deck with given ID, in the order they were put in it.
+// If the deck is not found, listing.ErrNotFound is returned.
+func (r *Repository) FindPile(ID uuid.UUID, name string) ([]listing.Card, error) {
+	var exists int
+	err := r.db.QueryRowContext(r.ctx, "SELECT 1 FROM decks WHERE deck_id = $1", ID).Scan(&exists)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, listing.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return r.findListedCards(ID, true, name)
+}
+
+// MoveCards locks the deck with ID deckID, passes its drawn cards to pick, puts the picked cards on top of pile and
+// records the move on behalf of requester.
+func (r *Repository) MoveCards(deckID uuid.UUID, pile, requester string, pick drawing.Pick) ([]drawing.Card, error) {
+	tx, err := r.db.BeginTx(r.ctx, nil)
+	if err != nil {
+		return []drawing.Card{}, fmt.Errorf("error at creating transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := r.lockDeck(tx, deckID); err != nil {
+		return []drawing.Card{}, err
+	}
+
+	drawn, err := r.findCards(tx, deckID, true)
+	if err != nil {
+		return []drawing.Card{}, err
+	}
+
+	cards, err := pick(drawn)
+	if err != nil {
+		return []drawing.Card{}, err
+	}
+
+	if 0 == len(cards) {
+		return cards, tx.Commit()
+	}
+
+	if cards, err = r.pileCards(tx, deckID, pile, cards); err != nil {
+		return []drawing.Card{}, fmt.Errorf("error at putting cards in pile: %v", err)
+	}
+
+	if err = r.recordDraw(tx, deckID, history.ActionMove, pile, requester, cards); err != nil {
+		return []drawing.Card{}, fmt.Errorf("error at recording move: %v", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return []drawing.Card{}, err
+	}
+
+	return cards, nil
+}
+
+// pileCards puts given drawn cards on top of pile in the given order and returns them in the pile. Positions of drawn
+// cards order their piles, so the cards are given positions above the top card of the pile.
+func (r *Repository) pileCards(tx *sql.Tx, deckID uuid.UUID, pile string, cards []drawing.Card) ([]drawing.Card, error) {
+	var top int
+	query := "SELECT COALESCE(MAX(position), -1) + 1 FROM cards WHERE deck = $1 AND drawn = $2 AND pile = $3"
+	if err := tx.QueryRowContext(r.ctx, query, deckID, true, pile).Scan(&top); err != nil {
+		return nil, err
+	}
+
+	var piled []drawing.Card
+	statement := "UPDATE cards SET pile = $1, position = $2 WHERE deck = $3 AND card_id = $4"
+	for i, c := range cards {
+		if _, err := tx.ExecContext(r.ctx, statement, pile, top+i, deckID, c.ID); err != nil {
+			return nil, err
+		}
+
+		c.Pile = pile
+		piled = append(piled, c)
+	}
+
+	return piled, nil
+}
+
+// DealCards locks the deck with ID deckID, passes its available cards to split, marks the cards of the hands as drawn,
+// puts each hand in the pile of its seat and records every hand on behalf of requester, all in one transaction.
+func (r *Repository) DealCards(deckID uuid.UUID, requester string, split drawing.Split) ([]drawing.Hand, error) {
+	tx, err := r.db.BeginTx(r.ctx, nil)
+	if err != nil {
+		return []drawing.Hand{}, fmt.Errorf("error at creating transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := r.lockDeck(tx, deckID); err != nil {
+		return []drawing.Hand{}, err
+	}
+
+	available, err := r.findCards(tx, deckID, false)
+	if err != nil {
+		return []drawing.Hand{}, err
+	}
+
+	if 0 == len(available) {
+		return []drawing.Hand{}, drawing.ErrNotFound
+	}
+
+	hands, err := split(available)
+	if err != nil {
+		return []drawing.Hand{}, err
+	}
+
+	for i, h := range hands {
+		if 0 == len(h.Cards) {
+			continue
+		}
+
+		if err = r.markDrawn(tx, deckID, h.Cards); err != nil {
+			return []drawing.Hand{}, err
+		}
+
+		if hands[i].Cards, err = r.pileCards(tx, deckID, h.Seat, h.Cards); err != nil {
+			return []drawing.Hand{}, fmt.Errorf("error at putting cards in pile: %v", err)
+		}
+
+		if err = r.recordDraw(tx, deckID, history.ActionDeal, h.Seat, requester, hands[i].Cards); err != nil {
+			return []drawing.Hand{}, fmt.Errorf("error at recording deal: %v", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return []drawing.Hand{}, err
+	}
+
+	return hands, nil
+}
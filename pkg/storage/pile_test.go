@@ -0,0 +1,227 @@
+package storage_test
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/srgyrn/lucky-38/pkg/creating"
+	"github.com/srgyrn/lucky-38/pkg/drawing"
+	"github.com/srgyrn/lucky-38/pkg/history"
+	"github.com/srgyrn/lucky-38/pkg/listing"
+)
+
+func TestRepository_piles(t *testing.T) {
+	r := getRepository(t)
+	defer r.TestTeardown(t)
+
+	migration := getMigrationSQL(t, filepath.Join("testdata", "migrations", "insert_deck.sql"))
+	r.TestInitData(t, migration)
+	deckID, _ := uuid.Parse("a251071b-662f-44b6-ba11-e24863039c59")
+	s := drawing.NewService(r)
+
+	t.Run("missing deck", func(t *testing.T) {
+		missingDeckID, _ := uuid.Parse("69077400-88cd-11eb-8dcd-0242ac130003")
+		if _, err := r.FindPile(missingDeckID, "dealer"); !errors.Is(err, listing.ErrNotFound) {
+			t.Errorf("FindPile() want error = %v got %v", listing.ErrNotFound, err)
+		}
+
+		if _, err := r.MoveCards(missingDeckID, "dealer", "test", nil); !errors.Is(err, drawing.ErrNotFound) {
+			t.Errorf("MoveCards() want error = %v got %v", drawing.ErrNotFound, err)
+		}
+	})
+
+	t.Run("draw into piles", func(t *testing.T) {
+		got, _, err := s.DrawInto(deckID.String(), "player1", 2, "dealer")
+		if err != nil {
+			t.Fatalf("DrawInto() error = %v", err)
+		}
+
+		want := []drawing.Card{
+			{ID: 1, Code: "AS", Value: "ACE", Suit: "SPADES", Rank: 1, Color: "BLACK", Pile: "player1"},
+			{ID: 2, Code: "2S", Value: "2", Suit: "SPADES", Rank: 2, Color: "BLACK", Pile: "player1"},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("DrawInto() = %v, want %v", got, want)
+		}
+
+		if _, _, err := s.DrawInto(deckID.String(), "dealer", 1, "dealer"); err != nil {
+			t.Fatalf("DrawInto() error = %v", err)
+		}
+
+		deck, err := r.Find(deckID)
+		if err != nil {
+			t.Fatalf("Find() error = %v", err)
+		}
+
+		wantPiles := listing.Piles{"player1": 2, "dealer": 1}
+		if !reflect.DeepEqual(deck.Piles, wantPiles) || !reflect.DeepEqual(codes(deck.Cards), []string{"4S"}) {
+			t.Errorf("Find() piles %v with cards %v, want %v with 4S", deck.Piles, codes(deck.Cards), wantPiles)
+		}
+
+		pile, err := r.FindPile(deckID, "player1")
+		if err != nil {
+			t.Fatalf("FindPile() error = %v", err)
+		}
+
+		if !reflect.DeepEqual(codes(pile), []string{"AS", "2S"}) {
+			t.Errorf("FindPile() = %v, want AS,2S", pile)
+		}
+	})
+
+	t.Run("move between piles", func(t *testing.T) {
+		got, err := s.Move(deckID.String(), "player1", "dealer", []string{"2S"}, "dealer")
+		if err != nil {
+			t.Fatalf("Move() error = %v", err)
+		}
+
+		want := []drawing.Card{{ID: 2, Code: "2S", Value: "2", Suit: "SPADES", Rank: 2, Color: "BLACK", Pile: "dealer"}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Move() = %v, want %v", got, want)
+		}
+
+		pile, err := r.FindPile(deckID, "dealer")
+		if err != nil {
+			t.Fatalf("FindPile() error = %v", err)
+		}
+
+		if !reflect.DeepEqual(codes(pile), []string{"3S", "2S"}) {
+			t.Errorf("FindPile() = %v, want 3S,2S", pile)
+		}
+
+		if _, err := s.Move(deckID.String(), "player1", "dealer", []string{"5S"}, "dealer"); !errors.Is(err, drawing.ErrCardNotInPile) {
+			t.Errorf("Move() want error = %v got %v", drawing.ErrCardNotInPile, err)
+		}
+	})
+
+	t.Run("return pile", func(t *testing.T) {
+		got, err := s.ReturnPile(deckID.String(), "dealer", false, "dealer")
+		if err != nil {
+			t.Fatalf("ReturnPile() error = %v", err)
+		}
+
+		if 2 != len(got) || "3S" != got[0].Code || "2S" != got[1].Code {
+			t.Errorf("ReturnPile() = %v, want 3S,2S", got)
+		}
+
+		deck, err := r.Find(deckID)
+		if err != nil {
+			t.Fatalf("Find() error = %v", err)
+		}
+
+		wantPiles := listing.Piles{"player1": 1}
+		if !reflect.DeepEqual(deck.Piles, wantPiles) || !reflect.DeepEqual(codes(deck.Cards), []string{"4S", "3S", "2S"}) || 3 != deck.Remaining {
+			t.Errorf("Find() piles %v with %d cards %v, want %v with 4S,3S,2S", deck.Piles, deck.Remaining, codes(deck.Cards), wantPiles)
+		}
+
+		pile, err := r.FindPile(deckID, "dealer")
+		if err != nil || 0 != len(pile) {
+			t.Errorf("FindPile() = %v, %v, want an empty pile", pile, err)
+		}
+	})
+
+	t.Run("history", func(t *testing.T) {
+		draws, err := r.FindDraws(deckID)
+		if err != nil {
+			t.Fatalf("FindDraws() error = %v", err)
+		}
+
+		var got []history.Draw
+		for _, d := range draws {
+			got = append(got, history.Draw{Action: d.Action, Pile: d.Pile})
+		}
+
+		want := []history.Draw{
+			{Action: history.ActionDraw, Pile: "player1"},
+			{Action: history.ActionDraw, Pile: "dealer"},
+			{Action: history.ActionMove, Pile: "dealer"},
+			{Action: history.ActionReturn, Pile: "dealer"},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("FindDraws() = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestRepository_DealCards(t *testing.T) {
+	r := getRepository(t)
+	defer r.TestTeardown(t)
+
+	deck, err := creating.NewService(r).CreateDeck(creating.Deck{Remaining: creating.FrenchDeckCardTotal})
+	if err != nil {
+		t.Fatalf("CreateDeck() error = %v", err)
+	}
+	s := drawing.NewService(r)
+
+	t.Run("missing deck", func(t *testing.T) {
+		missingDeckID, _ := uuid.Parse("69077400-88cd-11eb-8dcd-0242ac130003")
+		if _, _, err := s.Deal(missingDeckID.String(), drawing.Seats(2), 1, "dealer"); !errors.Is(err, drawing.ErrNotFound) {
+			t.Errorf("Deal() want error = %v got %v", drawing.ErrNotFound, err)
+		}
+	})
+
+	t.Run("not enough cards deals nothing", func(t *testing.T) {
+		if _, _, err := s.Deal(deck.ID.String(), drawing.Seats(6), 9, "dealer"); !errors.Is(err, drawing.ErrInsufficientRemainingCard) {
+			t.Fatalf("Deal() want error = %v got %v", drawing.ErrInsufficientRemainingCard, err)
+		}
+
+		listed, err := r.Find(deck.ID)
+		if err != nil {
+			t.Fatalf("Find() error = %v", err)
+		}
+
+		if creating.FrenchDeckCardTotal != listed.Remaining || nil != listed.Piles {
+			t.Errorf("Find() remaining %d with piles %v, want a full deck", listed.Remaining, listed.Piles)
+		}
+	})
+
+	t.Run("round robin", func(t *testing.T) {
+		hands, _, err := s.Deal(deck.ID.String(), []string{"north", "east", "south"}, 2, "dealer")
+		if err != nil {
+			t.Fatalf("Deal() error = %v", err)
+		}
+
+		want := map[string][]string{"north": {"AS", "4S"}, "east": {"2S", "5S"}, "south": {"3S", "6S"}}
+		for _, h := range hands {
+			pile, err := r.FindPile(deck.ID, h.Seat)
+			if err != nil {
+				t.Fatalf("FindPile() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(codes(pile), want[h.Seat]) || 2 != len(h.Cards) || h.Seat != h.Cards[0].Pile {
+				t.Errorf("Deal() hand %v in pile %v, want %v", h, codes(pile), want[h.Seat])
+			}
+		}
+
+		listed, err := r.Find(deck.ID)
+		if err != nil {
+			t.Fatalf("Find() error = %v", err)
+		}
+
+		if creating.FrenchDeckCardTotal-6 != listed.Remaining || "7S" != listed.Cards[0].Code {
+			t.Errorf("Find() remaining %d from %s, want %d from 7S", listed.Remaining, listed.Cards[0].Code, creating.FrenchDeckCardTotal-6)
+		}
+
+		draws, err := r.FindDraws(deck.ID)
+		if err != nil {
+			t.Fatalf("FindDraws() error = %v", err)
+		}
+
+		if 3 != len(draws) || history.ActionDeal != draws[0].Action || "north" != draws[0].Pile || "AS" != draws[0].Cards[0].Code {
+			t.Errorf("FindDraws() = %v, want a deal for each seat", draws)
+		}
+	})
+}
+
+// codes returns codes of given cards in order
+func codes(cards []listing.Card) []string {
+	var got []string
+	for _, c := range cards {
+		got = append(got, c.Code)
+	}
+
+	return got
+}
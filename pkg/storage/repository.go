@@ -10,94 +10,375 @@ import (
 
 	"github.com/google/uuid"
 	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
 
+	"github.com/srgyrn/lucky-38/pkg/config"
 	"github.com/srgyrn/lucky-38/pkg/creating"
 	"github.com/srgyrn/lucky-38/pkg/drawing"
+	"github.com/srgyrn/lucky-38/pkg/history"
 	"github.com/srgyrn/lucky-38/pkg/listing"
 )
 
-// Repository holds connection to db and implements creating.Repository
-type Repository struct {
-	ctx context.Context
-	db  *sql.DB
-}
+type (
+	// Repository holds connection to db and implements creating.Repository
+	Repository struct {
+		ctx    context.Context
+		db     *sql.DB
+		driver string
+	}
 
+	// queryer is implemented by both *sql.DB and *sql.Tx
+	queryer interface {
+		QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	}
+)
+
+// NewRepository opens a connection to the database with given driver and source.
+// Schema is not touched, run MigrateUp to bring it up to date.
 func NewRepository(driver, source string) (*Repository, error) {
+	if config.DriverSQLite == driver {
+		source = sqliteSource(source)
+	}
+
 	db, err := sql.Open(driver, source)
 	if err != nil {
 		return nil, fmt.Errorf("could not connect to db: %v", err)
 	}
 
-	return &Repository{db: db}, nil
+	return &Repository{ctx: context.Background(), db: db, driver: driver}, nil
 }
 
-// DrawCards updates drawn status to true of n number of cards from deck with ID deckID
-func (r *Repository) DrawCards(deckID uuid.UUID, cards ...drawing.Card) error {
-	var whereIn []string
-	for _, c := range cards {
-		whereIn = append(whereIn, strconv.Itoa(c.ID))
+// DrawCards locks the deck with ID deckID, passes its available cards to pick, marks the picked cards as drawn, puts
+// them in pile unless it is empty and records the draw on behalf of requester.
+// The deck row stays locked until the transaction ends, so concurrent draws on the same deck are served one by one.
+func (r *Repository) DrawCards(deckID uuid.UUID, pile, requester string, pick drawing.Pick) ([]drawing.Card, error) {
+	return r.drawCards(deckID, history.ActionDraw, pile, requester, pick)
+}
+
+// BurnCards draws the cards picked among available cards of the deck with ID deckID into drawing.BurnPile like
+// DrawCards does, recording a burn on behalf of requester.
+func (r *Repository) BurnCards(deckID uuid.UUID, requester string, pick drawing.Pick) ([]drawing.Card, error) {
+	return r.drawCards(deckID, history.ActionBurn, drawing.BurnPile, requester, pick)
+}
+
+// PeekCards locks the deck with ID deckID, passes its available cards to pick and records a peek at the picked cards
+// on behalf of requester. Cards stay as they are.
+func (r *Repository) PeekCards(deckID uuid.UUID, requester string, pick drawing.Pick) ([]drawing.Card, error) {
+	tx, err := r.db.BeginTx(r.ctx, nil)
+	if err != nil {
+		return []drawing.Card{}, fmt.Errorf("error at creating transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := r.lockDeck(tx, deckID); err != nil {
+		return []drawing.Card{}, err
+	}
+
+	available, err := r.findCards(tx, deckID, false)
+	if err != nil {
+		return []drawing.Card{}, err
+	}
+
+	if 0 == len(available) {
+		return []drawing.Card{}, drawing.ErrNotFound
+	}
+
+	cards, err := pick(available)
+	if err != nil {
+		return []drawing.Card{}, err
+	}
+
+	if err = r.recordDraw(tx, deckID, history.ActionPeek, "", requester, cards); err != nil {
+		return []drawing.Card{}, fmt.Errorf("error at recording peek: %v", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return []drawing.Card{}, err
 	}
 
-	r.ctx = context.Background()
+	return cards, nil
+}
+
+// drawCards draws the cards picked among available cards of the deck with ID deckID into pile, unless it is empty,
+// and records them as given action
+func (r *Repository) drawCards(deckID uuid.UUID, action, pile, requester string, pick drawing.Pick) ([]drawing.Card, error) {
 	tx, err := r.db.BeginTx(r.ctx, nil)
 	if err != nil {
-		return fmt.Errorf("error at creating transaction: %v", err)
+		return []drawing.Card{}, fmt.Errorf("error at creating transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := r.lockDeck(tx, deckID); err != nil {
+		return []drawing.Card{}, err
+	}
+
+	available, err := r.findCards(tx, deckID, false)
+	if err != nil {
+		return []drawing.Card{}, err
+	}
+
+	if 0 == len(available) {
+		return []drawing.Card{}, drawing.ErrNotFound
+	}
+
+	cards, err := pick(available)
+	if err != nil {
+		return []drawing.Card{}, err
+	}
+
+	if 0 == len(cards) {
+		return cards, tx.Commit()
+	}
+
+	if err = r.markDrawn(tx, deckID, cards); err != nil {
+		return []drawing.Card{}, err
+	}
+
+	if "" != pile {
+		if cards, err = r.pileCards(tx, deckID, pile, cards); err != nil {
+			return []drawing.Card{}, fmt.Errorf("error at putting cards in pile: %v", err)
+		}
+	}
+
+	if err = r.recordDraw(tx, deckID, action, pile, requester, cards); err != nil {
+		return []drawing.Card{}, fmt.Errorf("error at recording %s: %v", action, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return []drawing.Card{}, err
+	}
+
+	return cards, nil
+}
+
+// markDrawn marks given available cards as drawn and decreases remaining cards of the deck accordingly
+func (r *Repository) markDrawn(tx *sql.Tx, deckID uuid.UUID, cards []drawing.Card) error {
+	var whereIn []string
+	for _, c := range cards {
+		whereIn = append(whereIn, strconv.Itoa(c.ID))
 	}
 
 	// update cards, set drawn = true
-	statement := fmt.Sprintf("UPDATE cards SET drawn = true WHERE card_id IN (%s)", strings.Join(whereIn, ","))
-	_, err = tx.ExecContext(r.ctx, statement)
+	statement := fmt.Sprintf("UPDATE cards SET drawn = true WHERE deck = $1 AND drawn = $2 AND card_id IN (%s)", strings.Join(whereIn, ","))
+	result, err := tx.ExecContext(r.ctx, statement, deckID, false)
 	if err != nil {
-		tx.Rollback()
 		return err
 	}
 
-	// update decks, set remaining = remaining - number_of_cards_drawn
-	_, err = tx.ExecContext(r.ctx, fmt.Sprintf("UPDATE decks SET remaining = remaining - %d WHERE deck_id = $1", len(cards)), deckID)
+	drawn, err := result.RowsAffected()
 	if err != nil {
-		tx.Rollback()
 		return err
 	}
 
-	return tx.Commit()
+	// update decks, set remaining = remaining - number_of_cards_drawn
+	_, err = tx.ExecContext(r.ctx, fmt.Sprintf("UPDATE decks SET remaining = remaining - %d WHERE deck_id = $1", drawn), deckID)
+
+	return err
 }
 
 //FindAvailableCardByDeckID finds cards that are not drawn from the deck with given ID
 func (r *Repository) FindAvailableCardByDeckID(deckID uuid.UUID) ([]drawing.Card, error) {
-	query := `SELECT card_id, code, suit, value FROM cards WHERE deck = $1 AND drawn = $2 ORDER BY card_id DESC`
-	rows, err := r.db.Query(query, deckID, false)
+	cards, err := r.findCards(r.db, deckID, false)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return []drawing.Card{}, drawing.ErrNotFound
-		}
-
 		return []drawing.Card{}, err
 	}
+
+	if 0 == len(cards) {
+		return cards, drawing.ErrNotFound
+	}
+
+	return cards, nil
+}
+
+// findCards queries cards of the deck with given ID by their drawn status, top of the deck first
+func (r *Repository) findCards(q queryer, deckID uuid.UUID, drawn bool) ([]drawing.Card, error) {
+	query := `SELECT card_id, code, suit, value, copy, reversed, pile FROM cards WHERE deck = $1 AND drawn = $2 ORDER BY position, card_id`
+	rows, err := q.QueryContext(r.ctx, query, deckID, drawn)
+	if err != nil {
+		return nil, err
+	}
 	defer rows.Close()
 
 	var cards []drawing.Card
-	var cardIDs []string
 	for rows.Next() {
 		card := drawing.Card{}
-		err = rows.Scan(&card.ID, &card.Code, &card.Suit, &card.Value)
+		err = rows.Scan(&card.ID, &card.Code, &card.Suit, &card.Value, &card.Copy, &card.Reversed, &card.Pile)
 		if err != nil {
-			return []drawing.Card{}, err
+			return nil, err
 		}
 		cards = append(cards, card)
-		cardIDs = append(cardIDs, strconv.Itoa(card.ID))
 	}
 
-	if 0 == len(cards) {
-		return cards, drawing.ErrNotFound
+	return cards, rows.Err()
+}
+
+// ReturnCards locks the deck with ID deckID, passes its drawn cards to pick, puts the picked cards back to the deck
+// out of their piles and records the return from pile on behalf of requester. Then, available cards are given new
+// positions as arranged, and a shuffle is recorded if shuffled is set.
+func (r *Repository) ReturnCards(deckID uuid.UUID, pile, requester string, shuffled bool, pick drawing.Pick, arrange drawing.Arrange) ([]drawing.Card, error) {
+	tx, err := r.db.BeginTx(r.ctx, nil)
+	if err != nil {
+		return []drawing.Card{}, fmt.Errorf("error at creating transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := r.lockDeck(tx, deckID); err != nil {
+		return []drawing.Card{}, err
+	}
+
+	drawn, err := r.findCards(tx, deckID, true)
+	if err != nil {
+		return []drawing.Card{}, err
+	}
+
+	cards, err := pick(drawn)
+	if err != nil {
+		return []drawing.Card{}, err
+	}
+
+	if 0 < len(cards) {
+		var whereIn []string
+		for _, c := range cards {
+			whereIn = append(whereIn, strconv.Itoa(c.ID))
+		}
+
+		statement := fmt.Sprintf("UPDATE cards SET drawn = false, pile = '' WHERE deck = $1 AND drawn = $2 AND card_id IN (%s)", strings.Join(whereIn, ","))
+		result, err := tx.ExecContext(r.ctx, statement, deckID, true)
+		if err != nil {
+			return []drawing.Card{}, err
+		}
+
+		returned, err := result.RowsAffected()
+		if err != nil {
+			return []drawing.Card{}, err
+		}
+
+		_, err = tx.ExecContext(r.ctx, fmt.Sprintf("UPDATE decks SET remaining = remaining + %d WHERE deck_id = $1", returned), deckID)
+		if err != nil {
+			return []drawing.Card{}, err
+		}
+
+		if err = r.recordDraw(tx, deckID, history.ActionReturn, pile, requester, cards); err != nil {
+			return []drawing.Card{}, fmt.Errorf("error at recording return: %v", err)
+		}
+	}
+
+	available, err := r.findCards(tx, deckID, false)
+	if err != nil {
+		return []drawing.Card{}, err
+	}
+
+	arranged, err := arrange(append([]drawing.Card(nil), available...))
+	if err != nil {
+		return []drawing.Card{}, fmt.Errorf("error at arranging cards: %v", err)
+	}
+
+	if len(arranged) != len(available) {
+		return []drawing.Card{}, fmt.Errorf("error at arranging cards: got %d cards, want %d", len(arranged), len(available))
+	}
+
+	if err := r.arrangeCards(tx, deckID, arranged); err != nil {
+		return []drawing.Card{}, fmt.Errorf("error at arranging cards: %v", err)
+	}
+
+	if shuffled {
+		if err = r.recordDraw(tx, deckID, history.ActionShuffle, "", requester, nil); err != nil {
+			return []drawing.Card{}, fmt.Errorf("error at recording shuffle: %v", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return []drawing.Card{}, err
 	}
 
 	return cards, nil
 }
 
-// Find queries DB for the given deck ID and returns listing.Deck if found.
+// CutCards locks the deck with ID deckID, gives its available cards new positions in the order rotate returns them
+// and records the cut at the position rotate returns on behalf of requester.
+func (r *Repository) CutCards(deckID uuid.UUID, requester string, rotate drawing.Rotate) error {
+	tx, err := r.db.BeginTx(r.ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error at creating transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := r.lockDeck(tx, deckID); err != nil {
+		return err
+	}
+
+	available, err := r.findCards(tx, deckID, false)
+	if err != nil {
+		return err
+	}
+
+	rotated, position, err := rotate(append([]drawing.Card(nil), available...))
+	if err != nil {
+		return err
+	}
+
+	if len(rotated) != len(available) {
+		return fmt.Errorf("error at cutting cards: got %d cards, want %d", len(rotated), len(available))
+	}
+
+	if err := r.arrangeCards(tx, deckID, rotated); err != nil {
+		return fmt.Errorf("error at cutting cards: %v", err)
+	}
+
+	if err = r.recordDrawAt(tx, deckID, history.ActionCut, "", requester, position, nil); err != nil {
+		return fmt.Errorf("error at recording cut: %v", err)
+	}
+
+	return tx.Commit()
+}
+
+// FindCutCard returns the number of available cards left when the cut card of the deck with given ID is reached.
+// If the deck is not found, drawing.ErrNotFound is returned.
+func (r *Repository) FindCutCard(deckID uuid.UUID) (int, error) {
+	var cutCard int
+	err := r.db.QueryRowContext(r.ctx, "SELECT cut_card FROM decks WHERE deck_id = $1", deckID).Scan(&cutCard)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, drawing.ErrNotFound
+	}
+
+	return cutCard, err
+}
+
+// arrangeCards sets position of given cards to their index, so they are dealt in the given order
+func (r *Repository) arrangeCards(tx *sql.Tx, deckID uuid.UUID, cards []drawing.Card) error {
+	statement := "UPDATE cards SET position = $1 WHERE deck = $2 AND card_id = $3"
+	for i, c := range cards {
+		if _, err := tx.ExecContext(r.ctx, statement, i, deckID, c.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lockDeck locks the row of the deck with given ID until tx ends.
+// If the deck is not found, drawing.ErrNotFound is returned. If the deck is closed, drawing.ErrDeckClosed is returned.
+func (r *Repository) lockDeck(tx *sql.Tx, deckID uuid.UUID) error {
+	var closed bool
+	err := tx.QueryRowContext(r.ctx, "SELECT closed FROM decks WHERE deck_id = $1"+r.lockClause(), deckID).Scan(&closed)
+	if errors.Is(err, sql.ErrNoRows) {
+		return drawing.ErrNotFound
+	}
+
+	if err == nil && closed {
+		return drawing.ErrDeckClosed
+	}
+
+	return err
+}
+
+// Find queries DB for the given deck ID and returns listing.Deck if found, with available cards top of the deck first.
 func (r *Repository) Find(ID uuid.UUID) (listing.Deck, error) {
 	var deck listing.Deck
-	err := r.db.QueryRow("SELECT deck_id, remaining, shuffled FROM decks WHERE deck_id = $1", ID).Scan(&deck.ID, &deck.Remaining, &deck.Shuffled)
+	var seed sql.NullInt64
+	var commitment sql.NullString
+	err := r.db.QueryRow("SELECT deck_id, remaining, shuffled, seed, commitment, closed, cut_card FROM decks WHERE deck_id = $1", ID).
+		Scan(&deck.ID, &deck.Remaining, &deck.Shuffled, &seed, &commitment, &deck.Closed, &deck.CutCard)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return listing.Deck{}, listing.ErrNotFound
@@ -106,31 +387,65 @@ func (r *Repository) Find(ID uuid.UUID) (listing.Deck, error) {
 		return listing.Deck{}, err
 	}
 
-	query := `SELECT card_id, code, suit, value FROM cards WHERE deck = $1 AND drawn = $2`
-	rows, err := r.db.Query(query, ID, false)
+	if seed.Valid {
+		deck.Seed = &seed.Int64
+	}
+	deck.Commitment = commitment.String
+
+	deck.Cards, err = r.findListedCards(ID, false, "")
 	if err != nil {
 		return listing.Deck{}, err
 	}
+
+	rows, err := r.db.Query("SELECT pile, COUNT(*) FROM cards WHERE deck = $1 AND drawn = $2 AND pile <> '' GROUP BY pile", ID, true)
+	if err != nil {
+		return listing.Deck{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var pile string
+		var count int
+		if err = rows.Scan(&pile, &count); err != nil {
+			return listing.Deck{}, err
+		}
+
+		if nil == deck.Piles {
+			deck.Piles = make(listing.Piles)
+		}
+		deck.Piles[pile] = count
+	}
+
+	return deck, rows.Err()
+}
+
+// findListedCards queries cards of the deck with given ID by their drawn status and pile in order
+func (r *Repository) findListedCards(ID uuid.UUID, drawn bool, pile string) ([]listing.Card, error) {
+	query := `SELECT card_id, code, suit, value, copy, reversed FROM cards WHERE deck = $1 AND drawn = $2 AND pile = $3 ORDER BY position, card_id`
+	rows, err := r.db.Query(query, ID, drawn, pile)
+	if err != nil {
+		return nil, err
+	}
 	defer rows.Close()
 
+	var cards []listing.Card
 	for rows.Next() {
 		card := listing.Card{}
-		err = rows.Scan(&card.ID, &card.Code, &card.Suit, &card.Value)
+		err = rows.Scan(&card.ID, &card.Code, &card.Suit, &card.Value, &card.Copy, &card.Reversed)
 		if err != nil {
-			return listing.Deck{}, err
+			return nil, err
 		}
 
-		deck.Cards = append(deck.Cards, card)
+		cards = append(cards, card)
 	}
 
-	return deck, nil
+	return cards, rows.Err()
 }
 
-// CreateDeck inserts a new deck and cards to DB with given options
+// CreateDeck inserts a new deck and cards to DB with given options. The first card is placed on top of the deck.
 func (r *Repository) CreateDeck(deck *creating.Deck) error {
 	deck.ID = uuid.New()
 
-	r.ctx = context.Background()
 	tx, err := r.db.BeginTx(r.ctx, nil)
 	if err != nil {
 		return fmt.Errorf("error at creating transaction: %v", err)
@@ -155,8 +470,13 @@ func (r *Repository) CreateDeck(deck *creating.Deck) error {
 }
 
 func (r *Repository) insertDeck(tx *sql.Tx, deck *creating.Deck) error {
-	statement := "INSERT INTO decks (deck_id, shuffled, remaining) VALUES ($1, $2, $3)"
-	_, err := tx.ExecContext(r.ctx, statement, deck.ID, deck.Shuffled, deck.Remaining)
+	var seed sql.NullInt64
+	if deck.Seed != nil {
+		seed = sql.NullInt64{Int64: *deck.Seed, Valid: true}
+	}
+
+	statement := "INSERT INTO decks (deck_id, shuffled, remaining, seed, commitment, salt, cut_card) VALUES ($1, $2, $3, $4, $5, $6, $7)"
+	_, err := tx.ExecContext(r.ctx, statement, deck.ID, deck.Shuffled, deck.Remaining, seed, nullString(deck.Commitment), nullString(deck.Salt), deck.CutCard)
 	if err != nil {
 		tx.Rollback()
 		return err
@@ -166,24 +486,15 @@ func (r *Repository) insertDeck(tx *sql.Tx, deck *creating.Deck) error {
 }
 
 func (r *Repository) insertCard(tx *sql.Tx, deckID uuid.UUID, cards ...creating.Card) ([]creating.Card, error) {
-	var id int
-	err := r.db.QueryRow("SELECT nextval(pg_get_serial_sequence('cards', 'card_id')) AS id").Scan(&id)
-	if err != nil {
-		tx.Rollback()
-		return []creating.Card{}, errors.New("error retrieving next id")
-	}
-
 	var result []creating.Card
-	statement := "INSERT INTO cards (code, value, suit, drawn, deck) VALUES ($1, $2, $3, $4, $5)"
-	for _, c := range cards {
-		if _, err := tx.ExecContext(r.ctx, statement, c.Code, c.Value, c.Suit, false, deckID); err != nil {
+	statement := "INSERT INTO cards (code, value, suit, drawn, deck, position, copy, reversed) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING card_id"
+	for i, c := range cards {
+		if err := tx.QueryRowContext(r.ctx, statement, c.Code, c.Value, c.Suit, false, deckID, i, c.Copy, c.Reversed).Scan(&c.ID); err != nil {
 			tx.Rollback()
 			return []creating.Card{}, fmt.Errorf("error at inserting card %v, err: %v", c, err)
 		}
 
-		c.ID = id
 		result = append(result, c)
-		id++
 	}
 
 	return result, nil
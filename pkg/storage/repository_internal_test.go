@@ -7,7 +7,6 @@ import (
 
 	"github.com/google/uuid"
 
-	"github.com/srgyrn/lucky-38/pkg/config"
 	"github.com/srgyrn/lucky-38/pkg/creating"
 )
 func TestRepository_insertDeck(t *testing.T) {
@@ -161,20 +160,7 @@ func TestRepository_insertCard(t *testing.T) {
 
 func getRepository(t *testing.T) *Repository {
 	t.Helper()
-	conf, err := config.Load("../../")
-	if err != nil {
-		t.Fatalf("config.Load() err: %v", err)
-	}
-
-	if conf.Driver == "" || conf.Source == "" {
-		t.Fatalf("unexpected value for driver(%s) or source(%s)", conf.Driver, conf.Source)
-	}
-
-	r, err := NewRepository(conf.Driver, conf.Source)
-	if err != nil {
-		t.Fatalf("storage.NewRepository() error = %v", err)
-	}
-
+	r := NewTestRepository(t)
 	r.ctx = context.TODO()
 
 	return r
@@ -6,13 +6,14 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"sync"
 	"testing"
 
 	"github.com/google/uuid"
 
-	"github.com/srgyrn/lucky-38/pkg/config"
 	"github.com/srgyrn/lucky-38/pkg/creating"
 	"github.com/srgyrn/lucky-38/pkg/drawing"
+	"github.com/srgyrn/lucky-38/pkg/history"
 	"github.com/srgyrn/lucky-38/pkg/listing"
 	"github.com/srgyrn/lucky-38/pkg/storage"
 )
@@ -140,25 +141,30 @@ func TestRepository_Find(t *testing.T) {
 			t.Errorf("Find() got = %v, want %v", got, want)
 		}
 	})
-}
 
-func getRepository(t *testing.T) *storage.Repository {
-	t.Helper()
-	conf, err := config.Load("../../")
-	if err != nil {
-		t.Fatalf("config.Load() err: %v", err)
-	}
+	t.Run("seeded deck", func(t *testing.T) {
+		defer r.TestTeardown(t)
 
-	if conf.Driver == "" || conf.Source == "" {
-		t.Fatalf("unexpected value for driver(%s) or source(%s)", conf.Driver, conf.Source)
-	}
+		seed := int64(38)
+		deck := creating.Deck{Shuffled: true, Remaining: 1, Seed: &seed, Cards: []creating.Card{{Code: "AS", Value: "ACE", Suit: "SPADES"}}}
+		if err := r.CreateDeck(&deck); err != nil {
+			t.Fatalf("CreateDeck() error = %v", err)
+		}
 
-	r, err := storage.NewRepository(conf.Driver, conf.Source)
-	if err != nil {
-		t.Fatalf("storage.NewRepository() error = %v", err)
-	}
+		got, err := r.Find(deck.ID)
+		if err != nil {
+			t.Fatalf("Find() error = %v", err)
+		}
+
+		if got.Seed == nil || seed != *got.Seed {
+			t.Errorf("Find() seed = %v, want %d", got.Seed, seed)
+		}
+	})
+}
 
-	return r
+func getRepository(t *testing.T) *storage.Repository {
+	t.Helper()
+	return storage.NewTestRepository(t)
 }
 
 func TestRepository_DrawCards(t *testing.T) {
@@ -169,25 +175,116 @@ func TestRepository_DrawCards(t *testing.T) {
 	r.TestInitData(t, migration)
 
 	deckID, _ := uuid.Parse("a251071b-662f-44b6-ba11-e24863039c59")
-	drawnCards := []drawing.Card{{ID: 4}, {ID: 5}}
-	n := 2
-	wantRemaining := r.TestDeckRemaining(t, deckID) - n
+	wantRemaining := r.TestDeckRemaining(t, deckID)
+
+	t.Run("missing deck", func(t *testing.T) {
+		missingDeckID, _ := uuid.Parse("69077400-88cd-11eb-8dcd-0242ac130003")
+		_, err := r.DrawCards(missingDeckID, "", "test", func(available []drawing.Card) ([]drawing.Card, error) {
+			return available, nil
+		})
+		if !errors.Is(err, drawing.ErrNotFound) {
+			t.Errorf("DrawCards() want error = %v got %v", drawing.ErrNotFound, err)
+		}
+	})
+
+	t.Run("pick fails", func(t *testing.T) {
+		_, err := r.DrawCards(deckID, "", "test", func(available []drawing.Card) ([]drawing.Card, error) {
+			return nil, drawing.ErrInsufficientRemainingCard
+		})
+		if !errors.Is(err, drawing.ErrInsufficientRemainingCard) {
+			t.Errorf("DrawCards() want error = %v got %v", drawing.ErrInsufficientRemainingCard, err)
+		}
+
+		if gotRemaining := r.TestDeckRemaining(t, deckID); gotRemaining != wantRemaining {
+			t.Errorf("deck remaining: %d, want: %d", gotRemaining, wantRemaining)
+		}
+	})
+
+	t.Run("valid draw", func(t *testing.T) {
+		n := 2
+		got, err := r.DrawCards(deckID, "", "test", func(available []drawing.Card) ([]drawing.Card, error) {
+			var cards []drawing.Card
+			for _, c := range available {
+				if 4 == c.ID || 5 == c.ID {
+					cards = append(cards, c)
+				}
+			}
+
+			return cards, nil
+		})
+		if err != nil {
+			t.Errorf("DrawCards() error = %v", err)
+			return
+		}
+
+		want := []drawing.Card{
+			{ID: 4, Value: "4", Suit: "SPADES", Code: "4S"},
+			{ID: 5, Value: "5", Suit: "SPADES", Code: "5S"},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("DrawCards() = %v, want %v", got, want)
+		}
+
+		gotRemaining := r.TestDeckRemaining(t, deckID)
+		if gotRemaining != wantRemaining-n {
+			t.Errorf("deck remaining: %d, want: %d", gotRemaining, wantRemaining-n)
+			return
+		}
+
+		drawnCardCount := r.TestCountDrawnCards(t, deckID)
+		if drawnCardCount != n {
+			t.Errorf("drawn card count %d, want %d", drawnCardCount, n)
+		}
+	})
+}
+
+func TestRepository_DrawCards_concurrent(t *testing.T) {
+	r := getRepository(t)
+	defer r.TestTeardown(t)
 
-	err := r.DrawCards(deckID, drawnCards...)
+	deck, err := creating.NewService(r).CreateDeck(creating.Deck{Remaining: creating.FrenchDeckCardTotal})
 	if err != nil {
-		t.Errorf("DrawCards() error = %v", err)
-		return
+		t.Fatalf("CreateDeck() error = %v", err)
 	}
 
-	gotRemaining := r.TestDeckRemaining(t, deckID)
-	if gotRemaining != wantRemaining {
-		t.Errorf("deck remaining: %d, want: %d", gotRemaining, wantRemaining)
-		return
+	// more draws than the deck can serve, so some of them must fail
+	const draws = creating.FrenchDeckCardTotal/2 + 12
+	s := drawing.NewService(r)
+	results := make(chan []drawing.Card, draws)
+	var wg sync.WaitGroup
+	for i := 0; i < draws; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cards, _, err := s.Draw(deck.ID.String(), 2, "test")
+			if err != nil && !errors.Is(err, drawing.ErrNotFound) {
+				t.Errorf("Draw() error = %v", err)
+			}
+			results <- cards
+		}()
 	}
+	wg.Wait()
+	close(results)
 
-	drawnCardCount := r.TestCountDrawnCards(t, deckID)
-	if drawnCardCount != n {
-		t.Errorf("drawn card count %d, want %d", drawnCardCount, n)
+	dealt := make(map[int]int)
+	for cards := range results {
+		for _, c := range cards {
+			dealt[c.ID]++
+		}
+	}
+
+	if len(dealt) != creating.FrenchDeckCardTotal {
+		t.Errorf("dealt %d distinct cards, want %d", len(dealt), creating.FrenchDeckCardTotal)
+	}
+
+	for id, n := range dealt {
+		if n != 1 {
+			t.Errorf("card %d dealt %d times", id, n)
+		}
+	}
+
+	if remaining := r.TestDeckRemaining(t, deck.ID); remaining != 0 {
+		t.Errorf("deck remaining: %d, want: 0", remaining)
 	}
 }
 
@@ -201,16 +298,10 @@ func TestRepository_FindAvailableCardByDeckID(t *testing.T) {
 	deckID, _ := uuid.Parse("a251071b-662f-44b6-ba11-e24863039c59")
 	want := []drawing.Card{
 		{
-			ID:    4,
-			Value: "4",
-			Suit:  "SPADES",
-			Code:  "4S",
-		},
-		{
-			ID:    3,
-			Value: "3",
+			ID:    1,
+			Value: "ACE",
 			Suit:  "SPADES",
-			Code:  "3S",
+			Code:  "AS",
 		},
 		{
 			ID:    2,
@@ -219,10 +310,16 @@ func TestRepository_FindAvailableCardByDeckID(t *testing.T) {
 			Code:  "2S",
 		},
 		{
-			ID:    1,
-			Value: "ACE",
+			ID:    3,
+			Value: "3",
 			Suit:  "SPADES",
-			Code:  "AS",
+			Code:  "3S",
+		},
+		{
+			ID:    4,
+			Value: "4",
+			Suit:  "SPADES",
+			Code:  "4S",
 		},
 	}
 
@@ -254,4 +351,174 @@ func getMigrationSQL(t *testing.T, filepath string) string {
 
 	query, _ := ioutil.ReadAll(f)
 	return string(query)
-}
\ No newline at end of file
+}
+func TestRepository_ReturnCards(t *testing.T) {
+	r := getRepository(t)
+	defer r.TestTeardown(t)
+
+	migration := getMigrationSQL(t, filepath.Join("testdata", "migrations", "insert_deck.sql"))
+	r.TestInitData(t, migration)
+	deckID, _ := uuid.Parse("a251071b-662f-44b6-ba11-e24863039c59")
+
+	t.Run("missing deck", func(t *testing.T) {
+		missingDeckID, _ := uuid.Parse("69077400-88cd-11eb-8dcd-0242ac130003")
+		_, err := r.ReturnCards(missingDeckID, "", "test", false, returnAll, keepOrder)
+		if !errors.Is(err, drawing.ErrNotFound) {
+			t.Errorf("ReturnCards() want error = %v got %v", drawing.ErrNotFound, err)
+		}
+	})
+
+	t.Run("return and arrange", func(t *testing.T) {
+		// card 5 is drawn already, put it back and deal it first
+		got, err := r.ReturnCards(deckID, "", "dealer", false, returnAll, func(available []drawing.Card) ([]drawing.Card, error) {
+			for i, c := range available {
+				if "5S" == c.Code {
+					return append([]drawing.Card{c}, append(available[:i:i], available[i+1:]...)...), nil
+				}
+			}
+
+			t.Errorf("returned card is not available: %v", available)
+			return available, nil
+		})
+		if err != nil {
+			t.Fatalf("ReturnCards() error = %v", err)
+		}
+
+		want := []drawing.Card{{ID: 5, Value: "5", Suit: "SPADES", Code: "5S"}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ReturnCards() = %v, want %v", got, want)
+		}
+
+		if remaining := r.TestDeckRemaining(t, deckID); remaining != 5 {
+			t.Errorf("deck remaining: %d, want: 5", remaining)
+		}
+
+		available, err := r.FindAvailableCardByDeckID(deckID)
+		if err != nil {
+			t.Fatalf("FindAvailableCardByDeckID() error = %v", err)
+		}
+
+		var codes []string
+		for _, c := range available {
+			codes = append(codes, c.Code)
+		}
+
+		if wantCodes := []string{"5S", "AS", "2S", "3S", "4S"}; !reflect.DeepEqual(codes, wantCodes) {
+			t.Errorf("available cards %v, want %v", codes, wantCodes)
+		}
+
+		draws, err := r.FindDraws(deckID)
+		if err != nil {
+			t.Fatalf("FindDraws() error = %v", err)
+		}
+
+		if 1 != len(draws) || history.ActionReturn != draws[0].Action || "dealer" != draws[0].Requester {
+			t.Errorf("FindDraws() = %v, want a return by dealer", draws)
+		}
+	})
+
+	t.Run("shuffle without cards", func(t *testing.T) {
+		got, err := r.ReturnCards(deckID, "", "dealer", true, returnAll, keepOrder)
+		if err != nil || 0 != len(got) {
+			t.Fatalf("ReturnCards() = %v, %v, want no cards", got, err)
+		}
+
+		draws, err := r.FindDraws(deckID)
+		if err != nil {
+			t.Fatalf("FindDraws() error = %v", err)
+		}
+
+		if 2 != len(draws) || history.ActionShuffle != draws[1].Action || "dealer" != draws[1].Requester || !reflect.DeepEqual(draws[1].Cards, []history.Card{}) {
+			t.Errorf("FindDraws() = %v, want a return and a shuffle by dealer", draws)
+		}
+	})
+}
+
+func returnAll(drawn []drawing.Card) ([]drawing.Card, error) {
+	return drawn, nil
+}
+
+func keepOrder(available []drawing.Card) ([]drawing.Card, error) {
+	return available, nil
+}
+
+func TestRepository_DrawCards_modes(t *testing.T) {
+	r := getRepository(t)
+	defer r.TestTeardown(t)
+
+	migration := getMigrationSQL(t, filepath.Join("testdata", "migrations", "insert_deck.sql"))
+	r.TestInitData(t, migration)
+	deckID, _ := uuid.Parse("a251071b-662f-44b6-ba11-e24863039c59")
+	s := drawing.NewService(r)
+
+	got, _, err := s.DrawWith(deckID.String(), 2, drawing.Options{Mode: drawing.ModeBottom}, "test")
+	if err != nil {
+		t.Fatalf("DrawWith() error = %v", err)
+	}
+
+	if 2 != len(got) || "4S" != got[0].Code || "3S" != got[1].Code {
+		t.Errorf("DrawWith() bottom = %v, want 4S,3S", got)
+	}
+
+	opts := drawing.Options{Mode: drawing.ModeSpecific, Codes: []string{"2S", "3S"}}
+	if _, _, err := s.DrawWith(deckID.String(), 2, opts, "test"); !errors.Is(err, drawing.ErrCardNotAvailable) {
+		t.Errorf("DrawWith() want error = %v got %v", drawing.ErrCardNotAvailable, err)
+	}
+
+	opts.Codes = []string{"2S"}
+	got, _, err = s.DrawWith(deckID.String(), 1, opts, "test")
+	if err != nil {
+		t.Fatalf("DrawWith() error = %v", err)
+	}
+
+	if 1 != len(got) || "2S" != got[0].Code || 1 != r.TestDeckRemaining(t, deckID) {
+		t.Errorf("DrawWith() specific = %v with %d remaining, want 2S with 1", got, r.TestDeckRemaining(t, deckID))
+	}
+}
+
+func TestRepository_cut(t *testing.T) {
+	r := getRepository(t)
+	defer r.TestTeardown(t)
+
+	deck, err := creating.NewService(r).CreateDeck(creating.Deck{
+		Remaining:   4,
+		Penetration: 50,
+		Cards:       []creating.Card{{Code: "AS"}, {Code: "2S"}, {Code: "3S"}, {Code: "4S"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateDeck() error = %v", err)
+	}
+
+	s := drawing.NewService(r)
+	if _, err := s.Cut(deck.ID.String(), 1, "dealer"); err != nil {
+		t.Fatalf("Cut() error = %v", err)
+	}
+
+	found, err := r.Find(deck.ID)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+
+	if 2 != found.CutCard || !reflect.DeepEqual(codes(found.Cards), []string{"2S", "3S", "4S", "AS"}) {
+		t.Errorf("Find() cut card %d with cards %v, want 2 with 2S,3S,4S,AS", found.CutCard, codes(found.Cards))
+	}
+
+	for i, want := range []bool{false, true, true} {
+		if _, reached, err := s.Draw(deck.ID.String(), 1, "dealer"); err != nil || want != reached {
+			t.Errorf("Draw() %d reached = %v, error = %v, want %v", i+1, reached, err, want)
+		}
+	}
+
+	if _, err := s.Cut(deck.ID.String(), 1, "dealer"); !errors.Is(err, drawing.ErrInvalidCut) {
+		t.Errorf("Cut() want error = %v got %v", drawing.ErrInvalidCut, err)
+	}
+
+	draws, err := r.FindDraws(deck.ID)
+	if err != nil {
+		t.Fatalf("FindDraws() error = %v", err)
+	}
+
+	if 4 != len(draws) || history.ActionCut != draws[0].Action || 1 != draws[0].Position || !reflect.DeepEqual(draws[0].Cards, []history.Card{}) || "dealer" != draws[0].Requester {
+		t.Errorf("FindDraws() = %v, want a cut at 1 by dealer without cards and 3 draws", draws)
+	}
+}
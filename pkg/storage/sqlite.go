@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"strings"
+
+	"github.com/srgyrn/lucky-38/pkg/config"
+)
+
+// sqliteOptions are appended to SQLite sources unless they are set already:
+// foreign key checks are off unless asked per connection,
+// transactions take the write lock when they begin, so a transaction reading a deck cannot race another one and
+// busy connections wait for the lock instead of failing right away.
+var sqliteOptions = []string{"_foreign_keys=on", "_txlock=immediate", "_busy_timeout=5000"}
+
+// sqliteSource appends sqliteOptions to source
+func sqliteSource(source string) string {
+	for _, option := range sqliteOptions {
+		if strings.Contains(source, option[:strings.Index(option, "=")+1]) {
+			continue
+		}
+
+		separator := "?"
+		if strings.Contains(source, "?") {
+			separator = "&"
+		}
+		source += separator + option
+	}
+
+	return source
+}
+
+// lockClause returns the clause that locks selected rows until the transaction ends.
+// SQLite locks the whole database for the transaction instead, see sqliteOptions.
+func (r *Repository) lockClause() string {
+	if config.DriverSQLite == r.driver {
+		return ""
+	}
+
+	return " FOR UPDATE"
+}
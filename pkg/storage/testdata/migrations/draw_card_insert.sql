@@ -1,8 +1,8 @@
 INSERT INTO decks (deck_id, shuffled, remaining)
 VALUES ('a251071b-662f-44b6-ba11-e24863039c59', false, 4);
-INSERT INTO cards (card_id, code, value, suit, drawn, deck)
-VALUES (1, 'AS', 'ACE', 'SPADES', false, 'a251071b-662f-44b6-ba11-e24863039c59'),
-       (2, '2S', '2', 'SPADES', false, 'a251071b-662f-44b6-ba11-e24863039c59'),
-       (3, '3S', '3', 'SPADES', false, 'a251071b-662f-44b6-ba11-e24863039c59'),
-       (4, '4S', '4', 'SPADES', false, 'a251071b-662f-44b6-ba11-e24863039c59'),
-       (5, '5S', '5', 'SPADES', false, 'a251071b-662f-44b6-ba11-e24863039c59');
\ No newline at end of file
+INSERT INTO cards (card_id, code, value, suit, drawn, deck, position)
+VALUES (1, 'AS', 'ACE', 'SPADES', false, 'a251071b-662f-44b6-ba11-e24863039c59', 0),
+       (2, '2S', '2', 'SPADES', false, 'a251071b-662f-44b6-ba11-e24863039c59', 1),
+       (3, '3S', '3', 'SPADES', false, 'a251071b-662f-44b6-ba11-e24863039c59', 2),
+       (4, '4S', '4', 'SPADES', false, 'a251071b-662f-44b6-ba11-e24863039c59', 3),
+       (5, '5S', '5', 'SPADES', false, 'a251071b-662f-44b6-ba11-e24863039c59', 4);
\ No newline at end of file